@@ -0,0 +1,196 @@
+package api
+
+import (
+	"slices"
+	"sort"
+)
+
+// EntityDiff describes the changes between two snapshots of an entity.
+//
+// Records are identified by their ID, edges by their string representation
+// and duplicates by their key. All lists are sorted.
+type EntityDiff struct {
+	ID                *IDChange             `json:"id"`
+	RecordsAdded      []string              `json:"recordsAdded"`
+	RecordsRemoved    []string              `json:"recordsRemoved"`
+	RecordsVersioned  []RecordVersionChange `json:"recordsVersioned"`
+	EdgesAdded        Edges                 `json:"edgesAdded"`
+	EdgesRemoved      Edges                 `json:"edgesRemoved"`
+	DuplicatesChanged []DuplicatesChange    `json:"duplicatesChanged"`
+	Consistency       *ScoreChange          `json:"consistency"`
+	Score             *ScoreChange          `json:"score"`
+	HitScore          *ScoreChange          `json:"hitScore"`
+}
+
+// IDChange describes a changed entity ID.
+type IDChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// RecordVersionChange describes a record that is part of both snapshots, but
+// with different versions.
+type RecordVersionChange struct {
+	ID   string `json:"id"`
+	From int    `json:"from"`
+	To   int    `json:"to"`
+}
+
+// DuplicatesChange describes the changed duplicates for a single key of
+// Duplicates.
+type DuplicatesChange struct {
+	Key     string   `json:"key"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// ScoreChange describes a changed score.
+type ScoreChange struct {
+	From float64 `json:"from"`
+	To   float64 `json:"to"`
+}
+
+// DiffEntities compares two snapshots of an entity and returns the changes
+// from before to after.
+//
+// A nil entity is treated like an entity without any records, edges,
+// duplicates or scores, e.g. when comparing against a not yet existing entity.
+//
+// Edges are compared in their normalized form as created by NewEdge with the
+// lower record ID first, so that the same edge in different formats or with
+// swapped records is not considered a change. Edges that cannot be parsed are
+// compared as is.
+func DiffEntities(before, after *Entity) *EntityDiff {
+	if before == nil {
+		before = &Entity{}
+	}
+	if after == nil {
+		after = &Entity{}
+	}
+	diff := &EntityDiff{
+		RecordsVersioned:  []RecordVersionChange{},
+		DuplicatesChanged: []DuplicatesChange{},
+	}
+	if before.ID != after.ID {
+		diff.ID = &IDChange{From: before.ID, To: after.ID}
+	}
+	diffRecords(diff, before.Records, after.Records)
+	beforeEdges := normalizeEdges(before.Edges)
+	afterEdges := normalizeEdges(after.Edges)
+	diff.EdgesAdded = difference(afterEdges, beforeEdges)
+	diff.EdgesRemoved = difference(beforeEdges, afterEdges)
+	diffDuplicates(diff, before.Duplicates, after.Duplicates)
+	diff.Consistency = diffScore(before.Consistency, after.Consistency)
+	diff.Score = diffScore(before.Score, after.Score)
+	diff.HitScore = diffScore(before.HitScore, after.HitScore)
+	return diff
+}
+
+// IsEmpty returns true if both snapshots are equal.
+func (d *EntityDiff) IsEmpty() bool {
+	return d.ID == nil &&
+		len(d.RecordsAdded) == 0 &&
+		len(d.RecordsRemoved) == 0 &&
+		len(d.RecordsVersioned) == 0 &&
+		len(d.EdgesAdded) == 0 &&
+		len(d.EdgesRemoved) == 0 &&
+		len(d.DuplicatesChanged) == 0 &&
+		d.Consistency == nil &&
+		d.Score == nil &&
+		d.HitScore == nil
+}
+
+func diffRecords(diff *EntityDiff, before, after []*Record) {
+	beforeVersions := recordVersions(before)
+	afterVersions := recordVersions(after)
+	diff.RecordsAdded = difference(mapKeys(afterVersions), mapKeys(beforeVersions))
+	diff.RecordsRemoved = difference(mapKeys(beforeVersions), mapKeys(afterVersions))
+	for _, id := range mapKeys(afterVersions) {
+		from, ok := beforeVersions[id]
+		if ok && from != afterVersions[id] {
+			diff.RecordsVersioned = append(diff.RecordsVersioned, RecordVersionChange{
+				ID:   id,
+				From: from,
+				To:   afterVersions[id],
+			})
+		}
+	}
+}
+
+func recordVersions(records []*Record) map[string]int {
+	versions := make(map[string]int, len(records))
+	for _, record := range records {
+		versions[record.ID] = 0
+		if record.Meta != nil {
+			versions[record.ID] = record.Meta.Version
+		}
+	}
+	return versions
+}
+
+func normalizeEdges(edges Edges) []string {
+	normalized := make([]string, len(edges))
+	for i, edge := range edges {
+		normalized[i] = edge
+		if parsed, err := ParseEdgeStrict(edge); err == nil {
+			if parsed.B.ID < parsed.A.ID || (parsed.B.ID == parsed.A.ID && parsed.B.Version < parsed.A.Version) {
+				parsed.A, parsed.B = parsed.B, parsed.A
+			}
+			normalized[i] = parsed.String()
+		}
+	}
+	return normalized
+}
+
+func diffDuplicates(diff *EntityDiff, before, after Duplicates) {
+	keys := map[string]struct{}{}
+	for key := range before {
+		keys[key] = struct{}{}
+	}
+	for key := range after {
+		keys[key] = struct{}{}
+	}
+	for _, key := range mapKeys(keys) {
+		added := difference(after[key], before[key])
+		removed := difference(before[key], after[key])
+		if len(added) != 0 || len(removed) != 0 {
+			diff.DuplicatesChanged = append(diff.DuplicatesChanged, DuplicatesChange{
+				Key:     key,
+				Added:   added,
+				Removed: removed,
+			})
+		}
+	}
+}
+
+func diffScore(before, after float64) *ScoreChange {
+	if before == after {
+		return nil
+	}
+	return &ScoreChange{From: before, To: after}
+}
+
+// difference returns the sorted and unique values of a that are not in b.
+func difference(a, b []string) []string {
+	exclude := make(map[string]struct{}, len(b))
+	for _, v := range b {
+		exclude[v] = struct{}{}
+	}
+	result := []string{}
+	for _, v := range a {
+		if _, ok := exclude[v]; !ok {
+			result = append(result, v)
+		}
+	}
+	sort.Strings(result)
+	return slices.Compact(result)
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
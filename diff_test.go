@@ -0,0 +1,99 @@
+package api_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestDiffEntities(t *testing.T) {
+	before := &api.Entity{
+		ID: "e1",
+		Records: []*api.Record{
+			{ID: "r1"},
+			{ID: "r2", Meta: &api.RecordMeta{Version: 1}},
+			{ID: "r3"},
+		},
+		Edges: api.Edges{"r1:r2:R1", "r2:0:r3:0:R1:100"},
+		Duplicates: api.Duplicates{
+			":r1:0": []string{"d1", "d2"},
+			":r3:0": []string{"d3"},
+		},
+		Score:    0.5,
+		HitScore: 1,
+	}
+	after := &api.Entity{
+		ID: "e1",
+		Records: []*api.Record{
+			{ID: "r1"},
+			{ID: "r2", Meta: &api.RecordMeta{Version: 2}},
+			{ID: "r4"},
+		},
+		Edges: api.Edges{"r2:0:r1:0:R1:100", "r4:0:r2:0:R2:90"},
+		Duplicates: api.Duplicates{
+			":r1:0": []string{"d1", "d4"},
+		},
+		Score:    0.75,
+		HitScore: 1,
+	}
+
+	actual := api.DiffEntities(before, after)
+	assert.Equal(t, &api.EntityDiff{
+		RecordsAdded:     []string{"r4"},
+		RecordsRemoved:   []string{"r3"},
+		RecordsVersioned: []api.RecordVersionChange{{ID: "r2", From: 1, To: 2}},
+		EdgesAdded:       api.Edges{"r2:0:r4:0:R2:90"},
+		EdgesRemoved:     api.Edges{"r2:0:r3:0:R1:100"},
+		DuplicatesChanged: []api.DuplicatesChange{
+			{Key: ":r1:0", Added: []string{"d4"}, Removed: []string{"d2"}},
+			{Key: ":r3:0", Added: []string{}, Removed: []string{"d3"}},
+		},
+		Score: &api.ScoreChange{From: 0.5, To: 0.75},
+	}, actual)
+	assert.False(t, actual.IsEmpty())
+
+	j, err := json.Marshal(actual)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"id": null,
+		"recordsAdded": ["r4"],
+		"recordsRemoved": ["r3"],
+		"recordsVersioned": [{"id": "r2", "from": 1, "to": 2}],
+		"edgesAdded": ["r2:0:r4:0:R2:90"],
+		"edgesRemoved": ["r2:0:r3:0:R1:100"],
+		"duplicatesChanged": [
+			{"key": ":r1:0", "added": ["d4"], "removed": ["d2"]},
+			{"key": ":r3:0", "added": [], "removed": ["d3"]}
+		],
+		"consistency": null,
+		"score": {"from": 0.5, "to": 0.75},
+		"hitScore": null
+	}`, string(j))
+}
+
+func TestDiffEntitiesEqual(t *testing.T) {
+	entity := &api.Entity{
+		ID:      "e1",
+		Records: []*api.Record{{ID: "r1"}, {ID: "r2"}},
+		Edges:   api.Edges{"r1:r2:R1"},
+	}
+	assert.True(t, api.DiffEntities(entity, entity).IsEmpty())
+}
+
+func TestDiffEntitiesNil(t *testing.T) {
+	entity := &api.Entity{
+		ID:      "e1",
+		Records: []*api.Record{{ID: "r1"}},
+	}
+
+	actual := api.DiffEntities(nil, entity)
+	assert.Equal(t, &api.IDChange{From: "", To: "e1"}, actual.ID)
+	assert.Equal(t, []string{"r1"}, actual.RecordsAdded)
+
+	actual = api.DiffEntities(entity, nil)
+	assert.Equal(t, &api.IDChange{From: "e1", To: ""}, actual.ID)
+	assert.Equal(t, []string{"r1"}, actual.RecordsRemoved)
+}
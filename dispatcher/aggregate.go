@@ -0,0 +1,108 @@
+package dispatcher
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	api "github.com/tilotech/tilores-plugin-api"
+	"github.com/tilotech/tilores-plugin-api/internal/values"
+)
+
+// Aggregate calculates the aggregations over the given entities.
+//
+// The result contains one entry per aggregation name. An error is returned if
+// an aggregation has no or a duplicate name, an unknown type or an invalid
+// path.
+func Aggregate(entities []*api.Entity, aggregations []*Aggregation) (map[string]*AggregationResult, error) {
+	results := make(map[string]*AggregationResult, len(aggregations))
+	for _, aggregation := range aggregations {
+		if aggregation.Name == "" {
+			return nil, fmt.Errorf("missing aggregation name")
+		}
+		if _, ok := results[aggregation.Name]; ok {
+			return nil, fmt.Errorf("duplicate aggregation name %v", aggregation.Name)
+		}
+		path, err := api.ParsePath(aggregation.Path)
+		if err != nil {
+			return nil, err
+		}
+		switch aggregation.Type {
+		case AggregateTerms:
+			results[aggregation.Name] = aggregateTerms(entities, path, aggregation.Size)
+		case AggregateMin:
+			results[aggregation.Name] = aggregateExtreme(entities, path, -1)
+		case AggregateMax:
+			results[aggregation.Name] = aggregateExtreme(entities, path, 1)
+		default:
+			return nil, fmt.Errorf("invalid aggregation type %v", aggregation.Type)
+		}
+	}
+	return results, nil
+}
+
+func aggregateTerms(entities []*api.Entity, path api.Path, size *int) *AggregationResult {
+	counts := map[any]int{}
+	for _, entity := range entities {
+		seen := map[any]bool{}
+		for _, record := range entity.Records {
+			for _, value := range path.Resolve(record.Data) {
+				value = values.Normalize(value)
+				if value == nil {
+					continue
+				}
+				if !seen[value] {
+					seen[value] = true
+					counts[value]++
+				}
+			}
+		}
+	}
+	buckets := make([]*AggregationBucket, 0, len(counts))
+	for value, count := range counts {
+		buckets = append(buckets, &AggregationBucket{Value: value, Count: count})
+	}
+	slices.SortFunc(buckets, func(a, b *AggregationBucket) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return values.Compare(a.Value, b.Value)
+	})
+	if size != nil && *size >= 0 && *size < len(buckets) {
+		buckets = buckets[:*size]
+	}
+	return &AggregationResult{Buckets: buckets}
+}
+
+// aggregateExtreme returns the minimum (direction -1) or maximum (direction 1)
+// of all numbers or, if there are no numbers, of all times. Values are
+// normalized as for aggregateTerms, hence strings containing a number are
+// ignored.
+func aggregateExtreme(entities []*api.Entity, path api.Path, direction int) *AggregationResult {
+	var number *float64
+	var t *time.Time
+	for _, entity := range entities {
+		for _, record := range entity.Records {
+			for _, value := range path.Resolve(record.Data) {
+				switch v := values.Normalize(value).(type) {
+				case float64:
+					if number == nil || cmp.Compare(v, *number) == direction {
+						number = &v
+					}
+				case time.Time:
+					if t == nil || v.Compare(*t) == direction {
+						t = &v
+					}
+				}
+			}
+		}
+	}
+	switch {
+	case number != nil:
+		return &AggregationResult{Value: *number}
+	case t != nil:
+		return &AggregationResult{Value: *t}
+	}
+	return &AggregationResult{}
+}
@@ -0,0 +1,111 @@
+package dispatcher_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "github.com/tilotech/tilores-plugin-api"
+	"github.com/tilotech/tilores-plugin-api/dispatcher"
+)
+
+func TestAggregate(t *testing.T) {
+	entities := []*api.Entity{
+		{
+			ID: "e1",
+			Records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"country": "DE", "age": float64(30), "since": "2020-01-01T00:00:00Z"}},
+				{ID: "r2", Data: map[string]any{"country": "DE", "age": 31}},
+			},
+		},
+		{
+			ID: "e2",
+			Records: []*api.Record{
+				{ID: "r3", Data: map[string]any{"country": "FR", "age": float64(20), "since": "2021-01-01T00:00:00Z"}},
+				{ID: "r4", Data: map[string]any{"country": "DE"}},
+			},
+		},
+		{
+			ID: "e3",
+			Records: []*api.Record{
+				{ID: "r5", Data: map[string]any{"country": "AT", "age": int64(40), "since": time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}},
+			},
+		},
+	}
+	one := 1
+
+	actual, err := dispatcher.Aggregate(entities, []*dispatcher.Aggregation{
+		{Name: "countries", Type: dispatcher.AggregateTerms, Path: "country"},
+		{Name: "topCountry", Type: dispatcher.AggregateTerms, Path: "country", Size: &one},
+		{Name: "minAge", Type: dispatcher.AggregateMin, Path: "age"},
+		{Name: "maxAge", Type: dispatcher.AggregateMax, Path: "age"},
+		{Name: "firstSince", Type: dispatcher.AggregateMin, Path: "since"},
+		{Name: "lastSince", Type: dispatcher.AggregateMax, Path: "since"},
+		{Name: "none", Type: dispatcher.AggregateMax, Path: "unknown"},
+		{Name: "ages", Type: dispatcher.AggregateTerms, Path: "age", Size: &one},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*dispatcher.AggregationResult{
+		"countries": {
+			Buckets: []*dispatcher.AggregationBucket{
+				{Value: "DE", Count: 2},
+				{Value: "AT", Count: 1},
+				{Value: "FR", Count: 1},
+			},
+		},
+		"topCountry": {
+			Buckets: []*dispatcher.AggregationBucket{
+				{Value: "DE", Count: 2},
+			},
+		},
+		"minAge":     {Value: float64(20)},
+		"maxAge":     {Value: float64(40)},
+		"firstSince": {Value: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		"lastSince":  {Value: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+		"none":       {},
+		"ages": {
+			Buckets: []*dispatcher.AggregationBucket{
+				{Value: float64(20), Count: 1},
+			},
+		},
+	}, actual)
+}
+
+func TestAggregateInvalid(t *testing.T) {
+	for name, aggregations := range map[string][]*dispatcher.Aggregation{
+		"missing name":   {{Type: dispatcher.AggregateTerms, Path: "country"}},
+		"duplicate name": {{Name: "a", Type: dispatcher.AggregateTerms, Path: "country"}, {Name: "a", Type: dispatcher.AggregateMin, Path: "age"}},
+		"invalid type":   {{Name: "a", Type: "AVG", Path: "age"}},
+		"invalid path":   {{Name: "a", Type: dispatcher.AggregateMin, Path: "age["}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := dispatcher.Aggregate(nil, aggregations)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestAggregateValueConversion(t *testing.T) {
+	utc := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	entities := []*api.Entity{
+		{ID: "e1", Records: []*api.Record{{ID: "r1", Data: map[string]any{"v": float64(10), "t": utc, "n": "100"}}}},
+		{ID: "e2", Records: []*api.Record{{ID: "r2", Data: map[string]any{"v": float64(9), "t": utc.In(time.FixedZone("CET", 3600)), "n": float64(5)}}}},
+		{ID: "e3", Records: []*api.Record{{ID: "r3", Data: map[string]any{"t": "2024-01-01T13:00:00+01:00"}}}},
+	}
+
+	actual, err := dispatcher.Aggregate(entities, []*dispatcher.Aggregation{
+		{Name: "values", Type: dispatcher.AggregateTerms, Path: "v"},
+		{Name: "times", Type: dispatcher.AggregateTerms, Path: "t"},
+		{Name: "max", Type: dispatcher.AggregateMax, Path: "n"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*dispatcher.AggregationBucket{
+		{Value: float64(9), Count: 1},
+		{Value: float64(10), Count: 1},
+	}, actual["values"].Buckets)
+	assert.Equal(t, []*dispatcher.AggregationBucket{
+		{Value: utc, Count: 3},
+	}, actual["times"].Buckets)
+	assert.Equal(t, float64(5), actual["max"].Value)
+}
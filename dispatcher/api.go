@@ -2,6 +2,7 @@ package dispatcher
 
 import (
 	"context"
+	"time"
 
 	api "github.com/tilotech/tilores-plugin-api"
 )
@@ -18,48 +19,280 @@ import (
 type Dispatcher interface {
 	Entity(ctx context.Context, input *EntityInput) (*EntityOutput, error)
 	EntityByRecord(ctx context.Context, input *EntityByRecordInput) (*EntityOutput, error)
+	Entities(ctx context.Context, input *EntitiesInput) (*EntitiesOutput, error)
+	EntitiesByRecords(ctx context.Context, input *EntitiesByRecordsInput) (*EntitiesOutput, error)
+	EntityHistory(ctx context.Context, input *EntityHistoryInput) (*EntityHistoryOutput, error)
 	Submit(ctx context.Context, input *SubmitInput) (*SubmitOutput, error)
 	SubmitWithPreview(ctx context.Context, input *SubmitWithPreviewInput) (*SubmitWithPreviewOutput, error)
 	Search(ctx context.Context, input *SearchInput) (*SearchOutput, error)
 	Disassemble(ctx context.Context, input *DisassembleInput) (*DisassembleOutput, error)
 	RemoveConnectionBan(ctx context.Context, input *RemoveConnectionBanInput) error
+	ConnectionBans(ctx context.Context, input *ConnectionBansInput) (*ConnectionBansOutput, error)
+	RemoveRecords(ctx context.Context, input *RemoveRecordsInput) (*RemoveRecordsOutput, error)
+	SubmitStatus(ctx context.Context, input *SubmitStatusInput) (*SubmitStatusOutput, error)
+	Changes(ctx context.Context, input *ChangesInput) (*ChangesOutput, error)
+	Link(ctx context.Context, input *LinkInput) (*LinkOutput, error)
 }
 
 // EntityInput includes the data required to get an entity by its ID
+//
+// ConsiderRecords and ConsiderRecordsExpression restrict the records of the
+// entity, see api.CombineFilters.
+//
+// RecordOffset and RecordLimit can be used to only return a page of the
+// entities records, see EntityOutput.ApplyRecordPage.
 type EntityInput struct {
-	ID              string                 `json:"id"`
-	ConsiderRecords []*api.FilterCondition `json:"considerRecords"`
-	Features        api.Features           `json:"features"`
+	ID                        string                 `json:"id"`
+	ConsiderRecords           []*api.FilterCondition `json:"considerRecords"`
+	ConsiderRecordsExpression *api.FilterExpression  `json:"considerRecordsExpression"`
+	Features                  api.Features           `json:"features"`
+	RecordOffset              *int                   `json:"recordOffset"`
+	RecordLimit               *int                   `json:"recordLimit"`
 }
 
 // EntityByRecordInput includes the data required to get an entity by one of its record IDs
+//
+// ConsiderRecords and ConsiderRecordsExpression restrict the records of the
+// entity, see api.CombineFilters.
+//
+// RecordOffset and RecordLimit can be used to only return a page of the
+// entities records, see EntityOutput.ApplyRecordPage.
 type EntityByRecordInput struct {
-	ID              string                 `json:"id"`
-	ConsiderRecords []*api.FilterCondition `json:"considerRecords"`
-	Features        api.Features           `json:"features"`
+	ID                        string                 `json:"id"`
+	ConsiderRecords           []*api.FilterCondition `json:"considerRecords"`
+	ConsiderRecordsExpression *api.FilterExpression  `json:"considerRecordsExpression"`
+	Features                  api.Features           `json:"features"`
+	RecordOffset              *int                   `json:"recordOffset"`
+	RecordLimit               *int                   `json:"recordLimit"`
 }
 
 // EntityOutput the output of Entity call
+//
+// TotalRecords is the number of records of the entity, independent of the
+// requested page. It may be nil if the records were not paged.
 type EntityOutput struct {
+	Entity       *api.Entity `json:"entity"`
+	TotalRecords *int        `json:"totalRecords"`
+}
+
+// EntitiesInput includes the data required to get multiple entities by their IDs
+//
+// ConsiderRecords and ConsiderRecordsExpression restrict the records of the
+// entities, see api.CombineFilters.
+type EntitiesInput struct {
+	IDs                       []string               `json:"ids"`
+	ConsiderRecords           []*api.FilterCondition `json:"considerRecords"`
+	ConsiderRecordsExpression *api.FilterExpression  `json:"considerRecordsExpression"`
+	Features                  api.Features           `json:"features"`
+}
+
+// EntitiesByRecordsInput includes the data required to get multiple entities
+// by one of their record IDs each
+//
+// ConsiderRecords and ConsiderRecordsExpression restrict the records of the
+// entities, see api.CombineFilters.
+type EntitiesByRecordsInput struct {
+	IDs                       []string               `json:"ids"`
+	ConsiderRecords           []*api.FilterCondition `json:"considerRecords"`
+	ConsiderRecordsExpression *api.FilterExpression  `json:"considerRecordsExpression"`
+	Features                  api.Features           `json:"features"`
+}
+
+// EntitiesOutput the output of Entities and EntitiesByRecords call
+//
+// Results contains exactly one entry per requested ID in the same order as
+// the IDs of the input.
+type EntitiesOutput struct {
+	Results []*EntityResult `json:"results"`
+}
+
+// EntityResult is the result for a single ID of a batch lookup.
+//
+// ID is the requested entity or record ID. If the entity was found, then
+// Entity is set. If the lookup failed for this ID, then Error is set. If
+// neither is set, then no entity exists for the ID.
+type EntityResult struct {
+	ID     string      `json:"id"`
 	Entity *api.Entity `json:"entity"`
+	Error  *string     `json:"error"`
+}
+
+// EntityHistoryInput includes the data required to get the history of an
+// entity by its ID or by one of its record IDs
+//
+// Exactly one of EntityID and RecordID must be provided. Since and Until
+// optionally limit the changes to the given time range (both including).
+type EntityHistoryInput struct {
+	EntityID string     `json:"entityID"`
+	RecordID string     `json:"recordID"`
+	Since    *time.Time `json:"since"`
+	Until    *time.Time `json:"until"`
+}
+
+// EntityHistoryOutput the output of EntityHistory call
+//
+// Changes are sorted by their timestamp, starting with the oldest change.
+type EntityHistoryOutput struct {
+	Changes []*EntityHistoryChange `json:"changes"`
+}
+
+// EntityHistoryChange describes a single change in the history of an entity.
+//
+// PreviousEntityIDs lists the entities before and EntityIDs the entities after
+// the change, e.g. two entities before and one entity after a merge. RecordIDs
+// and Edges list the records and edges that were added or removed by the
+// change.
+//
+// Depending on the type, the change contains the metadata of the user who
+// triggered it: DisassembleMeta for EntityHistoryDisassemble and
+// EntityHistoryConnectionBanCreated, RemoveConnectionBanMeta for
+// EntityHistoryConnectionBanRemoved, RemoveRecordsMeta for
+// EntityHistoryRemoveRecords and LinkMeta for EntityHistoryLink.
+type EntityHistoryChange struct {
+	Timestamp               time.Time                `json:"timestamp"`
+	Type                    EntityHistoryChangeType  `json:"type"`
+	EntityIDs               []string                 `json:"entityIDs"`
+	PreviousEntityIDs       []string                 `json:"previousEntityIDs"`
+	RecordIDs               []string                 `json:"recordIDs"`
+	Edges                   api.Edges                `json:"edges"`
+	ConnectionBanReference  *string                  `json:"connectionBanReference"`
+	DisassembleMeta         *DisassembleMeta         `json:"disassembleMeta"`
+	RemoveConnectionBanMeta *RemoveConnectionBanMeta `json:"removeConnectionBanMeta"`
+	RemoveRecordsMeta       *RemoveRecordsMeta       `json:"removeRecordsMeta"`
+	LinkMeta                *LinkMeta                `json:"linkMeta"`
+}
+
+// EntityHistoryChangeType defines the kind of change in the history of an entity.
+type EntityHistoryChangeType string
+
+const (
+	// EntityHistorySubmit is used when records were submitted into the entity.
+	EntityHistorySubmit EntityHistoryChangeType = "SUBMIT"
+	// EntityHistoryMerge is used when multiple entities were merged into one.
+	EntityHistoryMerge EntityHistoryChangeType = "MERGE"
+	// EntityHistoryDisassemble is used when edges or records were removed
+	// using Disassemble.
+	EntityHistoryDisassemble EntityHistoryChangeType = "DISASSEMBLE"
+	// EntityHistoryConnectionBanCreated is used when a connection ban was
+	// created during Disassemble.
+	EntityHistoryConnectionBanCreated EntityHistoryChangeType = "CONNECTION_BAN_CREATED"
+	// EntityHistoryConnectionBanRemoved is used when a connection ban was
+	// removed using RemoveConnectionBan.
+	EntityHistoryConnectionBanRemoved EntityHistoryChangeType = "CONNECTION_BAN_REMOVED"
+	// EntityHistoryRemoveRecords is used when records were permanently
+	// removed using RemoveRecords.
+	EntityHistoryRemoveRecords EntityHistoryChangeType = "REMOVE_RECORDS"
+	// EntityHistoryLink is used when records or entities were manually linked
+	// using Link.
+	EntityHistoryLink EntityHistoryChangeType = "LINK"
+)
+
+// ChangesInput includes the parameters to read the entity change feed.
+//
+// Cursor continues reading after the last change of a previous call, as
+// returned in ChangesOutput.NextCursor. Without a Cursor the feed is read from
+// the oldest available change. Limit restricts the number of returned changes.
+type ChangesInput struct {
+	Cursor *string `json:"cursor"`
+	Limit  *int    `json:"limit"`
+}
+
+// ChangesOutput contains the entity changes in the order they happened.
+//
+// NextCursor is always set and can be stored to resume reading the feed later,
+// even if there are currently no more changes. HasMore tells whether further
+// changes are available right away.
+type ChangesOutput struct {
+	Changes    []*EntityChange `json:"changes"`
+	NextCursor *string         `json:"nextCursor"`
+	HasMore    bool            `json:"hasMore"`
+}
+
+// EntityChange describes a single change of one or more entities.
+//
+// PreviousEntityIDs contains the IDs of the entities before the change and
+// EntityIDs the IDs after the change, e.g. for EntityChangeMerged the merged
+// entities and the resulting entity. RecordIDs contains the affected records.
+type EntityChange struct {
+	Timestamp         time.Time        `json:"timestamp"`
+	Type              EntityChangeType `json:"type"`
+	EntityIDs         []string         `json:"entityIDs"`
+	PreviousEntityIDs []string         `json:"previousEntityIDs"`
+	RecordIDs         []string         `json:"recordIDs"`
 }
 
+// EntityChangeType defines the kind of an entity change.
+type EntityChangeType string
+
+const (
+	// EntityChangeCreated is used when a new entity was created.
+	EntityChangeCreated EntityChangeType = "CREATED"
+	// EntityChangeMerged is used when multiple entities were merged into one.
+	EntityChangeMerged EntityChangeType = "MERGED"
+	// EntityChangeSplit is used when an entity was split into multiple ones.
+	EntityChangeSplit EntityChangeType = "SPLIT"
+	// EntityChangeRecordAdded is used when records were added to an entity.
+	EntityChangeRecordAdded EntityChangeType = "RECORD_ADDED"
+	// EntityChangeRecordRemoved is used when records were removed from an
+	// entity that still exists afterwards.
+	EntityChangeRecordRemoved EntityChangeType = "RECORD_REMOVED"
+	// EntityChangeDeleted is used when an entity no longer exists, e.g. after
+	// all of its records were removed.
+	EntityChangeDeleted EntityChangeType = "DELETED"
+)
+
 // SearchInput includes the search parameters
+//
+// ConsiderRecords and ConsiderRecordsExpression restrict the records that are
+// considered during the search, see api.CombineFilters.
+//
+// Cursor continues a previous search after the last entity of that search, as
+// returned in SearchOutput.NextCursor. If Cursor is provided, then Page is
+// ignored, while all other parameters must remain the same as in the previous
+// search.
+//
+// ThenSort defines additional criteria that are used in the given order for
+// entities that are equal according to Sort.
+//
+// Aggregations are calculated over all entities matching the search, not only
+// over the returned page, and are returned in SearchOutput.Aggregations.
 type SearchInput struct {
-	Parameters      *api.SearchParameters  `json:"parameters"`
-	ConsiderRecords []*api.FilterCondition `json:"considerRecords"`
-	Page            *int                   `json:"page"`
-	PageSize        *int                   `json:"pageSize"`
-	Sort            *EntitySortCriteria    `json:"sort"`
-	SearchRules     *string                `json:"searchRules"`
-	Features        api.Features           `json:"features"`
+	Parameters                *api.SearchParameters  `json:"parameters"`
+	ConsiderRecords           []*api.FilterCondition `json:"considerRecords"`
+	ConsiderRecordsExpression *api.FilterExpression  `json:"considerRecordsExpression"`
+	Page                      *int                   `json:"page"`
+	PageSize                  *int                   `json:"pageSize"`
+	Cursor                    *string                `json:"cursor"`
+	Sort                      *EntitySortCriteria    `json:"sort"`
+	ThenSort                  []*EntitySortCriteria  `json:"thenSort"`
+	SearchRules               *string                `json:"searchRules"`
+	Features                  api.Features           `json:"features"`
+	Aggregations              []*Aggregation         `json:"aggregations"`
+}
+
+// SortCriteria returns Sort followed by ThenSort, skipping nil entries.
+func (i *SearchInput) SortCriteria() []*EntitySortCriteria {
+	criteria := make([]*EntitySortCriteria, 0, len(i.ThenSort)+1)
+	if i.Sort != nil {
+		criteria = append(criteria, i.Sort)
+	}
+	for _, c := range i.ThenSort {
+		if c != nil {
+			criteria = append(criteria, c)
+		}
+	}
+	return criteria
 }
 
 // EntitySortCriteria defines the criteria to sort the entity results during
 // search.
+//
+// Path is required when sorting by SortEntityByDataPath and must be a valid
+// api.Path.
 type EntitySortCriteria struct {
 	Field     EntitySortField      `json:"field"`
 	Direction *EntitySortDirection `json:"direction"`
+	Path      *string              `json:"path"`
 }
 
 // EntitySortField defines the properties that entities can be sorted by.
@@ -70,6 +303,18 @@ const (
 	SortEntityByID EntitySortField = "id"
 	// SortEntityByHitScore sorts entities by their hit score (descending by default).
 	SortEntityByHitScore EntitySortField = "hitScore"
+	// SortEntityByScore sorts entities by their score (descending by default).
+	SortEntityByScore EntitySortField = "score"
+	// SortEntityByConsistency sorts entities by their consistency (descending by default).
+	SortEntityByConsistency EntitySortField = "consistency"
+	// SortEntityByRecordCount sorts entities by their number of records (descending by default).
+	SortEntityByRecordCount EntitySortField = "recordCount"
+	// SortEntityByLatestSubmit sorts entities by the most recent submit
+	// timestamp of their records (descending by default).
+	SortEntityByLatestSubmit EntitySortField = "latestSubmitTimestamp"
+	// SortEntityByDataPath sorts entities by the values at the criterias path
+	// within the data of their records (ascending by default).
+	SortEntityByDataPath EntitySortField = "dataPath"
 )
 
 // EntitySortDirection defines the sort direction during entity sorting.
@@ -83,21 +328,158 @@ const (
 )
 
 // SearchOutput the output of Search call
+//
+// Total is the number of entities matching the search, independent of the
+// page. It may be nil if the implementation cannot determine it.
+//
+// HasMore is true if there are more entities after this page, which can be
+// requested using NextCursor. The cursor is an opaque token. Implementations
+// must ensure that following the cursors returns each matching entity exactly
+// once, even if data is submitted between the requests, e.g. by encoding the
+// position and the point in time of the first search in the cursor.
 type SearchOutput struct {
-	Entities []*api.Entity `json:"entities"`
+	Entities   []*api.Entity `json:"entities"`
+	Total      *int          `json:"total"`
+	HasMore    bool          `json:"hasMore"`
+	NextCursor *string       `json:"nextCursor"`
+
+	Aggregations map[string]*AggregationResult `json:"aggregations"`
+}
+
+// Aggregation defines a summary that is calculated over the values at the
+// path (see api.Path) within the data of the records of all matching entities.
+//
+// Name must be unique within the search and is used as key for the result.
+// Size limits the number of buckets for AggregateTerms.
+type Aggregation struct {
+	Name string          `json:"name"`
+	Type AggregationType `json:"type"`
+	Path string          `json:"path"`
+	Size *int            `json:"size"`
+}
+
+// AggregationType defines how the values of an aggregation are summarized.
+type AggregationType string
+
+const (
+	// AggregateTerms counts the entities per distinct value.
+	//
+	// Each entity is counted at most once per value, even if multiple of its
+	// records have the same value. Only strings, numbers, times and booleans
+	// are counted. Times, including strings in RFC 3339 format, are counted
+	// per instant and returned in UTC. Strings containing a number remain
+	// strings.
+	AggregateTerms AggregationType = "TERMS"
+	// AggregateMin returns the smallest numeric value, or the earliest time if
+	// there are no numeric values. Values are converted as for AggregateTerms.
+	AggregateMin AggregationType = "MIN"
+	// AggregateMax returns the largest numeric value, or the latest time if
+	// there are no numeric values.
+	AggregateMax AggregationType = "MAX"
+)
+
+// AggregationResult contains the result of a single aggregation.
+//
+// Buckets is only set for AggregateTerms and is sorted by count (descending)
+// and value. Value is only set for AggregateMin and AggregateMax and is either
+// a number or a time, or nil if no such value exists. Times are transferred as
+// strings in RFC 3339 format.
+type AggregationResult struct {
+	Buckets []*AggregationBucket `json:"buckets"`
+	Value   any                  `json:"value"`
+}
+
+// AggregationBucket contains the number of entities for a single value.
+type AggregationBucket struct {
+	Value any `json:"value"`
+	Count int `json:"count"`
 }
 
 // SubmitInput includes the data required to submit
+//
+// By default the whole submission fails if any of the records is invalid. If
+// AllowPartialSuccess is set, then invalid records are rejected while all
+// valid records are still submitted. The rejected records are reported in
+// SubmitOutput.Records.
+//
+// IdempotencyKey allows clients to safely retry a submission. A dispatcher
+// MUST NOT submit the records again if a previous submission with the same key
+// succeeded and MUST instead return the SubmitOutput of that submission. The
+// records of the repeated submission are not compared with the original ones.
+// Failed submissions are not remembered and can be retried with the same key.
 type SubmitInput struct {
-	Records []*api.Record `json:"records"`
+	Records             []*api.Record `json:"records"`
+	AllowPartialSuccess bool          `json:"allowPartialSuccess"`
+	IdempotencyKey      *string       `json:"idempotencyKey"`
 }
 
 // SubmitOutput provides additional information about a successful
 // data submission.
+//
+// Records contains the result for each submitted record in the same order as
+// the records in the SubmitInput.
+//
+// JobID can be used to track the assembly of the submitted records using
+// SubmitStatus. It is nil if the dispatcher does not support tracking.
 type SubmitOutput struct {
-	RecordsAdded int `json:"recordsAdded"`
+	RecordsAdded int                   `json:"recordsAdded"`
+	Records      []*SubmitRecordResult `json:"records"`
+	JobID        *string               `json:"jobID"`
+}
+
+// SubmitRecordResult describes the outcome of submitting a single record.
+//
+// Reason is only set for rejected records.
+type SubmitRecordResult struct {
+	ID     string             `json:"id"`
+	Status SubmitRecordStatus `json:"status"`
+	Reason *string            `json:"reason"`
 }
 
+// SubmitRecordStatus defines the outcome of submitting a single record.
+type SubmitRecordStatus string
+
+const (
+	// SubmitRecordAccepted is used for records that were added.
+	SubmitRecordAccepted SubmitRecordStatus = "ACCEPTED"
+	// SubmitRecordRejected is used for invalid records that were not added.
+	SubmitRecordRejected SubmitRecordStatus = "REJECTED"
+	// SubmitRecordUnchanged is used for records that were already submitted
+	// before with the exact same data.
+	SubmitRecordUnchanged SubmitRecordStatus = "UNCHANGED"
+)
+
+// SubmitStatusInput includes the job ID as returned in SubmitOutput.JobID.
+type SubmitStatusInput struct {
+	JobID string `json:"jobID"`
+}
+
+// SubmitStatusOutput informs about the assembly progress of a submission.
+//
+// Pending, Assembled and Failed contain the number of records in the
+// respective state. EntityIDs contains the IDs of the entities the assembled
+// records ended up in.
+type SubmitStatusOutput struct {
+	Status    SubmitJobStatus `json:"status"`
+	Pending   int             `json:"pending"`
+	Assembled int             `json:"assembled"`
+	Failed    int             `json:"failed"`
+	EntityIDs []string        `json:"entityIDs"`
+}
+
+// SubmitJobStatus defines the overall state of a submission.
+type SubmitJobStatus string
+
+const (
+	// SubmitJobPending is used while at least one record is not yet assembled.
+	SubmitJobPending SubmitJobStatus = "PENDING"
+	// SubmitJobCompleted is used when all records were assembled.
+	SubmitJobCompleted SubmitJobStatus = "COMPLETED"
+	// SubmitJobFailed is used when no record is pending anymore, but at least
+	// one record failed to assemble.
+	SubmitJobFailed SubmitJobStatus = "FAILED"
+)
+
 // SubmitWithPreviewInput includes the data required to submit and possible options.
 //
 // DryRun option ensures that no data is ingested, only that the preview is provided.
@@ -117,11 +499,15 @@ type SubmitWithPreviewOutput struct {
 //
 // The metadata is required when disassemble is triggered by a real person,
 // Otherwise it MAY be omitted.
+//
+// If Features is provided, then the resulting entities are returned in
+// DisassembleOutput.Entities, limited to the active features.
 type DisassembleInput struct {
 	Edges               []DisassembleEdge `json:"edges"`
 	RecordIDs           []string          `json:"recordIDs"`
 	CreateConnectionBan bool              `json:"createConnectionBan"`
 	Meta                *DisassembleMeta  `json:"meta"`
+	Features            *api.Features     `json:"features"`
 }
 
 // DisassembleEdge represents a single edge to be removed
@@ -138,8 +524,18 @@ type DisassembleMeta struct {
 
 // DisassembleOutput informs about removed records and edges as well as the
 // remaining entity ids
+//
+// ConnectionBanReference is only set if a connection ban was created and can
+// be used to remove it again. EntityIDs contains the IDs of all entities that
+// resulted from the disassemble. Entities is only set if features were
+// requested in the DisassembleInput.
 type DisassembleOutput struct {
-	Triggered bool `json:"triggered"`
+	Triggered              bool          `json:"triggered"`
+	RemovedEdges           api.Edges     `json:"removedEdges"`
+	RemovedRecords         []string      `json:"removedRecords"`
+	ConnectionBanReference *string       `json:"connectionBanReference"`
+	EntityIDs              []string      `json:"entityIDs"`
+	Entities               []*api.Entity `json:"entities"`
 }
 
 // RemoveConnectionBanInput contains the data required to remove a connection ban
@@ -155,3 +551,120 @@ type RemoveConnectionBanMeta struct {
 	User   string `json:"user"`
 	Reason string `json:"reason"`
 }
+
+// ConnectionBansInput contains the data required to list connection bans
+//
+// If EntityID or RecordID is provided, then only the connection bans
+// involving the entity or the entity of the record are listed. Otherwise all
+// connection bans are listed. Page starts at 1.
+type ConnectionBansInput struct {
+	EntityID string `json:"entityID"`
+	RecordID string `json:"recordID"`
+	Page     *int   `json:"page"`
+	PageSize *int   `json:"pageSize"`
+}
+
+// ConnectionBansOutput the output of ConnectionBans call
+//
+// HasMore is true if there are more connection bans on the following pages.
+type ConnectionBansOutput struct {
+	ConnectionBans []*ConnectionBan `json:"connectionBans"`
+	HasMore        bool             `json:"hasMore"`
+}
+
+// ConnectionBan prevents the involved entities from being assembled into one
+// entity again.
+//
+// User and Reason are taken from the DisassembleMeta of the disassemble that
+// created the connection ban.
+type ConnectionBan struct {
+	Reference string    `json:"reference"`
+	EntityIDs []string  `json:"entityIDs"`
+	RecordIDs []string  `json:"recordIDs"`
+	User      string    `json:"user"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// RemoveInput returns the input to remove the connection ban between the
+// given entity and all other involved entities.
+func (b *ConnectionBan) RemoveInput(entityID string, meta RemoveConnectionBanMeta) *RemoveConnectionBanInput {
+	others := make([]string, 0, len(b.EntityIDs))
+	for _, id := range b.EntityIDs {
+		if id != entityID {
+			others = append(others, id)
+		}
+	}
+	return &RemoveConnectionBanInput{
+		Reference: b.Reference,
+		EntityID:  entityID,
+		Others:    others,
+		Meta:      meta,
+	}
+}
+
+// RemoveRecordsInput contains the data required to permanently remove records
+//
+// Other than disassembling records, removing records deletes all their data,
+// e.g. to fulfill a right to erasure. The entities of the records are
+// recalculated afterwards.
+//
+// The metadata is required when the removal is triggered by a real person,
+// otherwise it MAY be omitted.
+type RemoveRecordsInput struct {
+	RecordIDs []string           `json:"recordIDs"`
+	Meta      *RemoveRecordsMeta `json:"meta"`
+}
+
+// RemoveRecordsMeta provides information who and why the records were removed
+type RemoveRecordsMeta struct {
+	User   string `json:"user"`
+	Reason string `json:"reason"`
+}
+
+// RemoveRecordsOutput informs about the removed records and the affected
+// entities
+//
+// RemovedRecords contains the IDs of the records that were found and removed.
+// AffectedEntityIDs contains the IDs of all entities that contained any of the
+// removed records, including those listed in DeletedEntityIDs, which no
+// longer exist because all of their records were removed.
+type RemoveRecordsOutput struct {
+	Triggered         bool     `json:"triggered"`
+	RemovedRecords    []string `json:"removedRecords"`
+	AffectedEntityIDs []string `json:"affectedEntityIDs"`
+	DeletedEntityIDs  []string `json:"deletedEntityIDs"`
+}
+
+// LinkInput contains the data required to manually link two records or two
+// entities
+//
+// Exactly two different IDs must be provided, either as RecordIDs or as
+// EntityIDs. Implementations must check this using LinkInput.Validate. The
+// link creates an edge with the rule api.RuleManual between the records, or in
+// case of entities between records chosen by the dispatcher, so that both end
+// up in the same entity.
+//
+// The metadata is required when the link is triggered by a real person,
+// otherwise it MAY be omitted.
+type LinkInput struct {
+	RecordIDs []string  `json:"recordIDs"`
+	EntityIDs []string  `json:"entityIDs"`
+	Meta      *LinkMeta `json:"meta"`
+}
+
+// LinkMeta provides information who and why the link was created
+type LinkMeta struct {
+	User   string `json:"user"`
+	Reason string `json:"reason"`
+}
+
+// LinkOutput informs about the created edge and the resulting entity
+//
+// Triggered is false if the records or entities were already part of the same
+// entity, in which case no edge was created.
+type LinkOutput struct {
+	Triggered bool      `json:"triggered"`
+	Edges     api.Edges `json:"edges"`
+	EntityID  string    `json:"entityID"`
+}
@@ -0,0 +1,99 @@
+package dispatcher
+
+import (
+	"slices"
+
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// SimulateDisassemble applies the disassemble input to the entity and returns
+// the resulting entities without changing any data.
+//
+// All edges between the records of each DisassembleEdge are removed,
+// independent of their rule. The records listed in RecordIDs are removed
+// together with all their edges, duplicates and hits. The remaining records
+// are then partitioned into entities by their remaining connections.
+//
+// The largest resulting entity keeps the ID of the original entity, all other
+// entities have an empty ID, because new IDs are only assigned during the
+// actual disassemble. Scores are not recalculated and remain zero. The
+// entity with the original ID comes first, all others are sorted by their
+// first record.
+//
+// Records that are duplicates of each other remain in the same entity, even
+// if they are not connected by an edge.
+//
+// If all records are removed, then the result is empty. An error is returned
+// if any of the record IDs in the input or any edge or duplicate key of the
+// entity cannot be parsed.
+func SimulateDisassemble(entity *api.Entity, input *DisassembleInput) ([]*api.Entity, error) {
+	removedRecords := map[string]bool{}
+	for _, recordID := range input.RecordIDs {
+		rid, err := api.ParseRecordIDStrict(recordID)
+		if err != nil {
+			return nil, err
+		}
+		removedRecords[rid.ID] = true
+	}
+	removedEdges := map[[2]string]bool{}
+	for _, edge := range input.Edges {
+		a, err := api.ParseRecordIDStrict(edge.A)
+		if err != nil {
+			return nil, err
+		}
+		b, err := api.ParseRecordIDStrict(edge.B)
+		if err != nil {
+			return nil, err
+		}
+		removedEdges[[2]string{a.ID, b.ID}] = true
+		removedEdges[[2]string{b.ID, a.ID}] = true
+	}
+
+	remaining := &api.Entity{
+		Duplicates: api.Duplicates{},
+		Hits:       entity.Hits,
+	}
+	for _, record := range entity.Records {
+		if !removedRecords[record.ID] {
+			remaining.Records = append(remaining.Records, record)
+		}
+	}
+	edges, err := entity.Edges.Parse()
+	if err != nil {
+		return nil, err
+	}
+	for i, edge := range edges {
+		a, b := edge.A.ID, edge.B.ID
+		if !removedEdges[[2]string{a, b}] && !removedRecords[a] && !removedRecords[b] {
+			remaining.Edges = append(remaining.Edges, entity.Edges[i])
+		}
+	}
+	for key, duplicates := range entity.Duplicates {
+		remaining.Duplicates[key] = slices.DeleteFunc(slices.Clone(duplicates), func(id string) bool {
+			return removedRecords[id]
+		})
+	}
+
+	graph, err := api.NewEntityGraph(remaining)
+	if err != nil {
+		return nil, err
+	}
+	components := graph.ConnectedComponents()
+	largest := 0
+	for i, component := range components {
+		if len(component) > len(components[largest]) {
+			largest = i
+		}
+	}
+	entities := make([]*api.Entity, 0, len(components))
+	for i, component := range components {
+		sub := remaining.SubEntity(component)
+		if i == largest {
+			sub.ID = entity.ID
+			entities = slices.Insert(entities, 0, sub)
+			continue
+		}
+		entities = append(entities, sub)
+	}
+	return entities, nil
+}
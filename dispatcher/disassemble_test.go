@@ -0,0 +1,156 @@
+package dispatcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "github.com/tilotech/tilores-plugin-api"
+	"github.com/tilotech/tilores-plugin-api/dispatcher"
+)
+
+func TestSimulateDisassemble(t *testing.T) {
+	entity := &api.Entity{
+		ID: "e1",
+		Records: []*api.Record{
+			{ID: "r1"}, {ID: "r2"}, {ID: "r3"}, {ID: "r4"}, {ID: "r5"},
+		},
+		Edges: api.Edges{
+			"r1:0:r2:0:R1:100",
+			"r1:0:r2:0:R2:100",
+			"r2:0:r3:0:R1:100",
+			"r3:0:r4:0:R1:100",
+			"r4:0:r5:0:R1:100",
+		},
+		Duplicates: api.Duplicates{
+			":r1:0": []string{"d1"},
+			":r5:0": []string{"d5", "r4"},
+		},
+		Hits: api.Hits{
+			"r1": []string{"R1"},
+			"r4": []string{"R1"},
+		},
+		Score: 0.8,
+	}
+
+	cases := map[string]struct {
+		input    *dispatcher.DisassembleInput
+		expected []*api.Entity
+	}{
+		"remove edge": {
+			input: &dispatcher.DisassembleInput{
+				Edges: []dispatcher.DisassembleEdge{{A: "r2", B: "r1"}},
+			},
+			expected: []*api.Entity{
+				{
+					ID:         "e1",
+					Records:    []*api.Record{{ID: "r2"}, {ID: "r3"}, {ID: "r4"}, {ID: "r5"}},
+					Edges:      api.Edges{"r2:0:r3:0:R1:100", "r3:0:r4:0:R1:100", "r4:0:r5:0:R1:100"},
+					Duplicates: api.Duplicates{":r5:0": []string{"d5", "r4"}},
+					Hits:       api.Hits{"r4": []string{"R1"}},
+				},
+				{
+					Records:    []*api.Record{{ID: "r1"}},
+					Edges:      api.Edges{},
+					Duplicates: api.Duplicates{":r1:0": []string{"d1"}},
+					Hits:       api.Hits{"r1": []string{"R1"}},
+				},
+			},
+		},
+		"remove record": {
+			input: &dispatcher.DisassembleInput{
+				RecordIDs: []string{"r4"},
+			},
+			expected: []*api.Entity{
+				{
+					ID:         "e1",
+					Records:    []*api.Record{{ID: "r1"}, {ID: "r2"}, {ID: "r3"}},
+					Edges:      api.Edges{"r1:0:r2:0:R1:100", "r1:0:r2:0:R2:100", "r2:0:r3:0:R1:100"},
+					Duplicates: api.Duplicates{":r1:0": []string{"d1"}},
+					Hits:       api.Hits{"r1": []string{"R1"}},
+				},
+				{
+					Records:    []*api.Record{{ID: "r5"}},
+					Edges:      api.Edges{},
+					Duplicates: api.Duplicates{":r5:0": []string{"d5"}},
+					Hits:       api.Hits{},
+				},
+			},
+		},
+		"remove non-existing edge": {
+			input: &dispatcher.DisassembleInput{
+				Edges: []dispatcher.DisassembleEdge{{A: "r1", B: "r5"}},
+			},
+			expected: []*api.Entity{
+				{
+					ID:         "e1",
+					Records:    entity.Records,
+					Edges:      entity.Edges,
+					Duplicates: entity.Duplicates,
+					Hits:       entity.Hits,
+				},
+			},
+		},
+		"remove all records": {
+			input: &dispatcher.DisassembleInput{
+				RecordIDs: []string{"r1", "r2", "r3", "r4", "r5"},
+			},
+			expected: []*api.Entity{},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := dispatcher.SimulateDisassemble(entity, c.input)
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestSimulateDisassembleKeepsDuplicatesTogether(t *testing.T) {
+	entity := &api.Entity{
+		ID:      "e1",
+		Records: []*api.Record{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}},
+		Edges:   api.Edges{"a:0:b:0:R1:100", "b:0:c:0:R1:100"},
+		Duplicates: api.Duplicates{
+			":a:0": []string{"d"},
+		},
+	}
+
+	actual, err := dispatcher.SimulateDisassemble(entity, &dispatcher.DisassembleInput{})
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	assert.Equal(t, entity.Records, actual[0].Records)
+
+	actual, err = dispatcher.SimulateDisassemble(entity, &dispatcher.DisassembleInput{
+		Edges: []dispatcher.DisassembleEdge{{A: "b", B: "c"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+	assert.Equal(t, []*api.Record{{ID: "a"}, {ID: "b"}, {ID: "d"}}, actual[0].Records)
+	assert.Equal(t, api.Duplicates{":a:0": []string{"d"}}, actual[0].Duplicates)
+	assert.Equal(t, []*api.Record{{ID: "c"}}, actual[1].Records)
+}
+
+func TestSimulateDisassembleInvalidInput(t *testing.T) {
+	entity := &api.Entity{
+		ID:      "e1",
+		Records: []*api.Record{{ID: "a"}, {ID: "b"}},
+		Edges:   api.Edges{"a:0:b:0:R1:100"},
+	}
+
+	cases := map[string]*dispatcher.DisassembleInput{
+		"invalid record version": {RecordIDs: []string{"a:x"}},
+		"empty record id":        {RecordIDs: []string{""}},
+		"invalid edge record a":  {Edges: []dispatcher.DisassembleEdge{{A: "a:x", B: "b"}}},
+		"invalid edge record b":  {Edges: []dispatcher.DisassembleEdge{{A: "a", B: "b:-1"}}},
+	}
+
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := dispatcher.SimulateDisassemble(entity, input)
+			assert.Error(t, err)
+		})
+	}
+}
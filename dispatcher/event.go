@@ -13,16 +13,25 @@ import (
 // The event can contain different types of payload depending on the action that
 // needs to be performed.
 //
-// In case of type "ASSEMBLE", the payload contains []*api.Record entries.
+// In case of type "ASSEMBLE", the payload contains []*api.Record entries and
+// the IdempotencyKey of the SubmitInput, if any, is provided on the event.
+// JobID is the same as in the SubmitOutput and allows the assemble process to
+// report its progress for SubmitStatus.
 //
 // In case of type "DISASSEMBLE", the payload is a *dispatcher.DisassembleInput.
 //
+// In case of type "REMOVE_RECORDS", the payload is a *dispatcher.RemoveRecordsInput.
+//
+// In case of type "LINK", the payload is a *dispatcher.LinkInput.
+//
 // For backwards compatibility, the old event input for assemble requests is
 // also supported. The output after unmarshalling will be the same as for type
 // "ASSEMBLE".
 type AssembleEvent struct {
-	Type    string `json:"type"`
-	Payload any    `json:"payload"`
+	Type           string  `json:"type"`
+	Payload        any     `json:"payload"`
+	IdempotencyKey *string `json:"idempotencyKey"`
+	JobID          *string `json:"jobID"`
 }
 
 const (
@@ -31,13 +40,21 @@ const (
 
 	// EventTypeDisassemble is used when the payload is for the disassemble process.
 	EventTypeDisassemble = "DISASSEMBLE"
+
+	// EventTypeRemoveRecords is used when the payload is for the record removal process.
+	EventTypeRemoveRecords = "REMOVE_RECORDS"
+
+	// EventTypeLink is used when the payload is for the manual link process.
+	EventTypeLink = "LINK"
 )
 
 // UnmarshalJSON parses the provided bytes and populates the AssembleEvent.
 func (r *AssembleEvent) UnmarshalJSON(b []byte) error {
 	partial := &struct {
-		Type    string
-		Payload json.RawMessage
+		Type           string
+		Payload        json.RawMessage
+		IdempotencyKey *string
+		JobID          *string
 	}{}
 	err := json.Unmarshal(b, partial)
 	if err != nil {
@@ -53,6 +70,12 @@ func (r *AssembleEvent) UnmarshalJSON(b []byte) error {
 	case EventTypeDisassemble:
 		payload = &DisassembleInput{}
 		err = json.Unmarshal(partial.Payload, payload)
+	case EventTypeRemoveRecords:
+		payload = &RemoveRecordsInput{}
+		err = json.Unmarshal(partial.Payload, payload)
+	case EventTypeLink:
+		payload = &LinkInput{}
+		err = json.Unmarshal(partial.Payload, payload)
 	default:
 		return fmt.Errorf("invalid type %s", partial.Type)
 	}
@@ -61,5 +84,7 @@ func (r *AssembleEvent) UnmarshalJSON(b []byte) error {
 	}
 	r.Type = partial.Type
 	r.Payload = payload
+	r.IdempotencyKey = partial.IdempotencyKey
+	r.JobID = partial.JobID
 	return nil
 }
@@ -41,6 +41,29 @@ func TestUnmarshalEvent(t *testing.T) {
 				},
 			},
 		},
+		"assemble with idempotency key": {
+			input: `
+				{
+					"type": "ASSEMBLE",
+					"idempotencyKey": "batch-1",
+					"jobID": "job-1",
+					"payload": [
+						{
+							"id": "foo"
+						}
+					]
+				}`,
+			expected: &dispatcher.AssembleEvent{
+				Type: "ASSEMBLE",
+				Payload: []*api.Record{
+					{
+						ID: "foo",
+					},
+				},
+				IdempotencyKey: pointer("batch-1"),
+				JobID:          pointer("job-1"),
+			},
+		},
 		"standard disassemble": {
 			input: `
 				{
@@ -66,6 +89,52 @@ func TestUnmarshalEvent(t *testing.T) {
 				},
 			},
 		},
+		"standard remove records": {
+			input: `
+				{
+					"type": "REMOVE_RECORDS",
+					"payload": {
+						"recordIDs": ["foo-1"],
+						"meta": {
+							"user": "someUser",
+							"reason": "someReason"
+						}
+					}
+				}`,
+			expected: &dispatcher.AssembleEvent{
+				Type: "REMOVE_RECORDS",
+				Payload: &dispatcher.RemoveRecordsInput{
+					RecordIDs: []string{"foo-1"},
+					Meta: &dispatcher.RemoveRecordsMeta{
+						User:   "someUser",
+						Reason: "someReason",
+					},
+				},
+			},
+		},
+		"standard link": {
+			input: `
+				{
+					"type": "LINK",
+					"payload": {
+						"recordIDs": ["foo-1", "foo-2"],
+						"meta": {
+							"user": "someUser",
+							"reason": "someReason"
+						}
+					}
+				}`,
+			expected: &dispatcher.AssembleEvent{
+				Type: "LINK",
+				Payload: &dispatcher.LinkInput{
+					RecordIDs: []string{"foo-1", "foo-2"},
+					Meta: &dispatcher.LinkMeta{
+						User:   "someUser",
+						Reason: "someReason",
+					},
+				},
+			},
+		},
 		"plain outdated assemble": {
 			input: `
 				[
@@ -0,0 +1,47 @@
+package dispatcher
+
+import (
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// ApplyFeatures replaces the entity with a copy that does not contain the
+// inactive features.
+//
+// See api.Features.Project for details. The output remains unchanged if an
+// error is returned.
+func (o *EntityOutput) ApplyFeatures(features api.Features) error {
+	entity, err := features.Project(o.Entity)
+	if err != nil {
+		return err
+	}
+	o.Entity = entity
+	return nil
+}
+
+// ApplyFeatures replaces the entities with copies that do not contain the
+// inactive features.
+//
+// See api.Features.Project for details. The output remains unchanged if an
+// error is returned.
+func (o *SearchOutput) ApplyFeatures(features api.Features) error {
+	entities, err := features.ProjectAll(o.Entities)
+	if err != nil {
+		return err
+	}
+	o.Entities = entities
+	return nil
+}
+
+// ApplyFeatures replaces the entities with copies that do not contain the
+// inactive features.
+//
+// See api.Features.Project for details. The output remains unchanged if an
+// error is returned.
+func (o *SubmitWithPreviewOutput) ApplyFeatures(features api.Features) error {
+	entities, err := features.ProjectAll(o.Entities)
+	if err != nil {
+		return err
+	}
+	o.Entities = entities
+	return nil
+}
@@ -0,0 +1,73 @@
+package dispatcher
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryIdempotencyStore is an in-memory reference implementation of the
+// idempotency contract of SubmitInput.IdempotencyKey.
+//
+// It is intended for fake and test dispatchers and does not expire any keys.
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	outputs map[string]*SubmitOutput
+	pending map[string]*idempotentCall
+}
+
+type idempotentCall struct {
+	done   chan struct{}
+	output *SubmitOutput
+	err    error
+}
+
+// NewMemoryIdempotencyStore creates a new empty MemoryIdempotencyStore.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		outputs: map[string]*SubmitOutput{},
+		pending: map[string]*idempotentCall{},
+	}
+}
+
+// Submit calls submit unless a previous call with the same idempotency key
+// succeeded, in which case the original output is returned.
+//
+// Concurrent calls with the same key wait for the first call and share its
+// result. Inputs without an idempotency key are always submitted.
+func (s *MemoryIdempotencyStore) Submit(ctx context.Context, input *SubmitInput, submit func(context.Context, *SubmitInput) (*SubmitOutput, error)) (*SubmitOutput, error) {
+	if input.IdempotencyKey == nil {
+		return submit(ctx, input)
+	}
+	key := *input.IdempotencyKey
+
+	s.mu.Lock()
+	if output, ok := s.outputs[key]; ok {
+		s.mu.Unlock()
+		return output, nil
+	}
+	if call, ok := s.pending[key]; ok {
+		s.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.output, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	call := &idempotentCall{
+		done: make(chan struct{}),
+	}
+	s.pending[key] = call
+	s.mu.Unlock()
+
+	call.output, call.err = submit(ctx, input)
+
+	s.mu.Lock()
+	delete(s.pending, key)
+	if call.err == nil {
+		s.outputs[key] = call.output
+	}
+	s.mu.Unlock()
+	close(call.done)
+	return call.output, call.err
+}
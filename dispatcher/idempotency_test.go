@@ -0,0 +1,88 @@
+package dispatcher_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "github.com/tilotech/tilores-plugin-api"
+	"github.com/tilotech/tilores-plugin-api/dispatcher"
+)
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	store := dispatcher.NewMemoryIdempotencyStore()
+	calls := 0
+	fail := true
+	submit := func(_ context.Context, input *dispatcher.SubmitInput) (*dispatcher.SubmitOutput, error) {
+		calls++
+		if fail {
+			return nil, fmt.Errorf("timeout")
+		}
+		return &dispatcher.SubmitOutput{RecordsAdded: len(input.Records)}, nil
+	}
+	input := &dispatcher.SubmitInput{
+		Records:        []*api.Record{{ID: "r1"}},
+		IdempotencyKey: pointer("batch-1"),
+	}
+
+	_, err := store.Submit(context.Background(), input, submit)
+	require.Error(t, err)
+
+	fail = false
+	output, err := store.Submit(context.Background(), input, submit)
+	require.NoError(t, err)
+	assert.Equal(t, 1, output.RecordsAdded)
+
+	repeated, err := store.Submit(context.Background(), &dispatcher.SubmitInput{
+		Records:        []*api.Record{{ID: "r1"}, {ID: "r2"}},
+		IdempotencyKey: pointer("batch-1"),
+	}, submit)
+	require.NoError(t, err)
+	assert.Same(t, output, repeated)
+	assert.Equal(t, 2, calls)
+
+	_, err = store.Submit(context.Background(), &dispatcher.SubmitInput{Records: input.Records}, submit)
+	require.NoError(t, err)
+	_, err = store.Submit(context.Background(), &dispatcher.SubmitInput{Records: input.Records}, submit)
+	require.NoError(t, err)
+	assert.Equal(t, 4, calls)
+}
+
+func TestMemoryIdempotencyStoreConcurrent(t *testing.T) {
+	store := dispatcher.NewMemoryIdempotencyStore()
+	var calls atomic.Int32
+	release := make(chan struct{})
+	submit := func(_ context.Context, _ *dispatcher.SubmitInput) (*dispatcher.SubmitOutput, error) {
+		calls.Add(1)
+		<-release
+		return &dispatcher.SubmitOutput{RecordsAdded: 1}, nil
+	}
+	input := &dispatcher.SubmitInput{IdempotencyKey: pointer("batch-1")}
+
+	outputs := make([]*dispatcher.SubmitOutput, 5)
+	wg := sync.WaitGroup{}
+	for i := range outputs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			output, err := store.Submit(context.Background(), input, submit)
+			assert.NoError(t, err)
+			outputs[i] = output
+		}()
+	}
+	close(release)
+	wg.Wait()
+
+	for _, output := range outputs {
+		assert.Same(t, outputs[0], output)
+	}
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func pointer[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,39 @@
+package dispatcher
+
+import (
+	"fmt"
+
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// Validate returns an error unless the input contains exactly two different
+// record IDs or exactly two different entity IDs.
+//
+// Record IDs must be valid as defined by api.ParseRecordIDStrict. The input is
+// not validated by the plugin transport, hence Dispatcher implementations must
+// call Validate before linking.
+func (i *LinkInput) Validate() error {
+	var ids []string
+	switch {
+	case len(i.RecordIDs) == 2 && len(i.EntityIDs) == 0:
+		ids = make([]string, len(i.RecordIDs))
+		for j, id := range i.RecordIDs {
+			rid, err := api.ParseRecordIDStrict(id)
+			if err != nil {
+				return err
+			}
+			ids[j] = rid.ID
+		}
+	case len(i.EntityIDs) == 2 && len(i.RecordIDs) == 0:
+		ids = i.EntityIDs
+		if ids[0] == "" || ids[1] == "" {
+			return fmt.Errorf("link requires non-empty entity IDs")
+		}
+	default:
+		return fmt.Errorf("link requires exactly two record IDs or two entity IDs")
+	}
+	if ids[0] == ids[1] {
+		return fmt.Errorf("link requires two different IDs, got %v twice", ids[0])
+	}
+	return nil
+}
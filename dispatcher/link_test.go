@@ -0,0 +1,40 @@
+package dispatcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tilotech/tilores-plugin-api/dispatcher"
+)
+
+func TestLinkInputValidate(t *testing.T) {
+	cases := map[string]struct {
+		input       *dispatcher.LinkInput
+		expectError bool
+	}{
+		"two records":         {input: &dispatcher.LinkInput{RecordIDs: []string{"r1", "r2:1"}}},
+		"two entities":        {input: &dispatcher.LinkInput{EntityIDs: []string{"e1", "e2"}}},
+		"nothing":             {input: &dispatcher.LinkInput{}, expectError: true},
+		"one record":          {input: &dispatcher.LinkInput{RecordIDs: []string{"r1"}}, expectError: true},
+		"three records":       {input: &dispatcher.LinkInput{RecordIDs: []string{"r1", "r2", "r3"}}, expectError: true},
+		"one entity":          {input: &dispatcher.LinkInput{EntityIDs: []string{"e1"}}, expectError: true},
+		"mixed":               {input: &dispatcher.LinkInput{RecordIDs: []string{"r1"}, EntityIDs: []string{"e1"}}, expectError: true},
+		"records and entity":  {input: &dispatcher.LinkInput{RecordIDs: []string{"r1", "r2"}, EntityIDs: []string{"e1"}}, expectError: true},
+		"same record":         {input: &dispatcher.LinkInput{RecordIDs: []string{"r1", "r1"}}, expectError: true},
+		"same record version": {input: &dispatcher.LinkInput{RecordIDs: []string{"r1", "r1:2"}}, expectError: true},
+		"same entity":         {input: &dispatcher.LinkInput{EntityIDs: []string{"e1", "e1"}}, expectError: true},
+		"invalid record":      {input: &dispatcher.LinkInput{RecordIDs: []string{"r1:x", "r2"}}, expectError: true},
+		"empty entity":        {input: &dispatcher.LinkInput{EntityIDs: []string{"e1", ""}}, expectError: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := c.input.Validate()
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package dispatcher
+
+// ApplyRecordPage replaces the entity with a copy that only contains the
+// records of the requested page and sets TotalRecords.
+//
+// A nil offset starts at the first record, a nil limit returns all remaining
+// records. If both are nil, the output is left unchanged. See
+// api.Entity.PageRecords for details.
+func (o *EntityOutput) ApplyRecordPage(offset, limit *int) {
+	if o.Entity == nil || offset == nil && limit == nil {
+		return
+	}
+	total := len(o.Entity.Records)
+	o.TotalRecords = &total
+	start, size := 0, -1
+	if offset != nil {
+		start = *offset
+	}
+	if limit != nil {
+		size = max(*limit, 0)
+	}
+	o.Entity = o.Entity.PageRecords(start, size)
+}
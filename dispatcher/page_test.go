@@ -0,0 +1,42 @@
+package dispatcher_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "github.com/tilotech/tilores-plugin-api"
+	"github.com/tilotech/tilores-plugin-api/dispatcher"
+)
+
+func TestEntityOutputApplyRecordPage(t *testing.T) {
+	entity := &api.Entity{
+		ID:      "e1",
+		Records: []*api.Record{{ID: "r1"}, {ID: "r2"}, {ID: "r3"}},
+		Edges:   api.Edges{"r1:r2:R1", "r2:r3:R1"},
+	}
+	offset, limit := 2, 5
+
+	output := &dispatcher.EntityOutput{Entity: entity}
+	output.ApplyRecordPage(&offset, &limit)
+	require.NotNil(t, output.TotalRecords)
+	assert.Equal(t, 3, *output.TotalRecords)
+	assert.Equal(t, []*api.Record{{ID: "r3"}}, output.Entity.Records)
+	assert.Empty(t, output.Entity.Edges)
+
+	offset, limit = 1, math.MaxInt
+	output = &dispatcher.EntityOutput{Entity: entity}
+	output.ApplyRecordPage(&offset, &limit)
+	assert.Equal(t, []*api.Record{{ID: "r2"}, {ID: "r3"}}, output.Entity.Records)
+	assert.Equal(t, api.Edges{"r2:r3:R1"}, output.Entity.Edges)
+
+	output = &dispatcher.EntityOutput{Entity: entity}
+	output.ApplyRecordPage(nil, nil)
+	assert.Same(t, entity, output.Entity)
+	assert.Nil(t, output.TotalRecords)
+
+	output = &dispatcher.EntityOutput{}
+	output.ApplyRecordPage(&offset, &limit)
+	assert.Nil(t, output.TotalRecords)
+}
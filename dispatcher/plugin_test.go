@@ -3,6 +3,7 @@ package dispatcher_test
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
@@ -38,6 +39,34 @@ func TestPlugin(t *testing.T) {
 	require.NotNil(t, entityByRecordOutput)
 	assert.Equal(t, entityOutput.Entity, entityByRecordOutput.Entity)
 
+	entitiesOutput, err := dsp.Entities(context.Background(), &dispatcher.EntitiesInput{IDs: []string{"abcd", "unknown", "invalid"}})
+	assert.NoError(t, err)
+	require.NotNil(t, entitiesOutput)
+	require.Len(t, entitiesOutput.Results, 3)
+	assert.Equal(t, "abcd", entitiesOutput.Results[0].ID)
+	assert.Equal(t, entityOutput.Entity, entitiesOutput.Results[0].Entity)
+	assert.Nil(t, entitiesOutput.Results[1].Entity)
+	assert.Nil(t, entitiesOutput.Results[1].Error)
+	require.NotNil(t, entitiesOutput.Results[2].Error)
+	assert.Equal(t, "invalid entity id", *entitiesOutput.Results[2].Error)
+
+	entitiesByRecordsOutput, err := dsp.EntitiesByRecords(context.Background(), &dispatcher.EntitiesByRecordsInput{IDs: []string{"12345"}})
+	assert.NoError(t, err)
+	require.NotNil(t, entitiesByRecordsOutput)
+	require.Len(t, entitiesByRecordsOutput.Results, 1)
+	assert.Equal(t, "12345", entitiesByRecordsOutput.Results[0].ID)
+	assert.Equal(t, entityOutput.Entity, entitiesByRecordsOutput.Results[0].Entity)
+
+	entityHistoryOutput, err := dsp.EntityHistory(context.Background(), &dispatcher.EntityHistoryInput{EntityID: "abcd"})
+	assert.NoError(t, err)
+	require.NotNil(t, entityHistoryOutput)
+	require.Len(t, entityHistoryOutput.Changes, 2)
+	assert.Equal(t, dispatcher.EntityHistorySubmit, entityHistoryOutput.Changes[0].Type)
+	assert.Equal(t, dispatcher.EntityHistoryDisassemble, entityHistoryOutput.Changes[1].Type)
+	require.NotNil(t, entityHistoryOutput.Changes[1].DisassembleMeta)
+	assert.Equal(t, "someUser", entityHistoryOutput.Changes[1].DisassembleMeta.User)
+	assert.True(t, entityHistoryOutput.Changes[0].Timestamp.Before(entityHistoryOutput.Changes[1].Timestamp))
+
 	parameters := &api.SearchParameters{
 		"foo": "bar",
 	}
@@ -55,9 +84,63 @@ func TestPlugin(t *testing.T) {
 				},
 			},
 		},
+		AllowPartialSuccess: true,
 	})
 	assert.NoError(t, err)
 	assert.Equal(t, 1, submitOutput.RecordsAdded)
+	assert.Equal(t, []*dispatcher.SubmitRecordResult{{ID: "12345", Status: dispatcher.SubmitRecordAccepted}}, submitOutput.Records)
+	require.NotNil(t, submitOutput.JobID)
+
+	submitStatusOutput, err := dsp.SubmitStatus(context.Background(), &dispatcher.SubmitStatusInput{JobID: *submitOutput.JobID})
+	assert.NoError(t, err)
+	assert.Equal(t, &dispatcher.SubmitStatusOutput{
+		Status:    dispatcher.SubmitJobCompleted,
+		Assembled: 1,
+		EntityIDs: []string{"abcd"},
+	}, submitStatusOutput)
+
+	_, err = dsp.SubmitStatus(context.Background(), &dispatcher.SubmitStatusInput{JobID: "unknown"})
+	assert.Error(t, err)
+
+	limit := 1
+	changesOutput, err := dsp.Changes(context.Background(), &dispatcher.ChangesInput{Limit: &limit})
+	assert.NoError(t, err)
+	require.NotNil(t, changesOutput)
+	assert.Equal(t, []*dispatcher.EntityChange{
+		{
+			Timestamp: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			Type:      dispatcher.EntityChangeCreated,
+			EntityIDs: []string{"abcd"},
+			RecordIDs: []string{"12345"},
+		},
+	}, changesOutput.Changes)
+	assert.True(t, changesOutput.HasMore)
+	require.NotNil(t, changesOutput.NextCursor)
+
+	changesOutput, err = dsp.Changes(context.Background(), &dispatcher.ChangesInput{Cursor: changesOutput.NextCursor, Limit: &limit})
+	assert.NoError(t, err)
+	require.Len(t, changesOutput.Changes, 1)
+	assert.Equal(t, dispatcher.EntityChangeMerged, changesOutput.Changes[0].Type)
+	assert.Equal(t, []string{"abcd", "efgh"}, changesOutput.Changes[0].PreviousEntityIDs)
+	assert.False(t, changesOutput.HasMore)
+	require.NotNil(t, changesOutput.NextCursor)
+	assert.Equal(t, "2", *changesOutput.NextCursor)
+
+	linkOutput, err := dsp.Link(context.Background(), &dispatcher.LinkInput{
+		RecordIDs: []string{"12345", "67890"},
+		Meta: &dispatcher.LinkMeta{
+			User:   "someUser",
+			Reason: "someReason",
+		},
+	})
+	assert.NoError(t, err)
+	require.NotNil(t, linkOutput)
+	assert.True(t, linkOutput.Triggered)
+	assert.Equal(t, api.Edges{"12345:0:67890:0:MANUAL:100"}, linkOutput.Edges)
+	assert.Equal(t, "abcd", linkOutput.EntityID)
+
+	_, err = dsp.Link(context.Background(), &dispatcher.LinkInput{RecordIDs: []string{"12345"}})
+	assert.EqualError(t, err, "link requires exactly two record IDs or two entity IDs")
 
 	submitWithPreviewOutput, err := dsp.SubmitWithPreview(context.Background(), &dispatcher.SubmitWithPreviewInput{
 		Records: []*api.Record{
@@ -88,9 +171,17 @@ func TestPlugin(t *testing.T) {
 			User:   "someUser",
 			Reason: "someReason",
 		},
+		Features: &api.Features{},
 	})
 	assert.NoError(t, err)
 	assert.True(t, disassembleOutput.Triggered)
+	assert.Equal(t, api.Edges{"abc:0:def:0:R1:100"}, disassembleOutput.RemovedEdges)
+	assert.Equal(t, []string{"12345"}, disassembleOutput.RemovedRecords)
+	require.NotNil(t, disassembleOutput.ConnectionBanReference)
+	assert.Equal(t, "123123", *disassembleOutput.ConnectionBanReference)
+	assert.Equal(t, []string{"abcd"}, disassembleOutput.EntityIDs)
+	require.Len(t, disassembleOutput.Entities, 1)
+	assert.Equal(t, "abcd", disassembleOutput.Entities[0].ID)
 
 	err = dsp.RemoveConnectionBan(context.Background(), &dispatcher.RemoveConnectionBanInput{
 		Reference: "123123",
@@ -103,6 +194,37 @@ func TestPlugin(t *testing.T) {
 	})
 	assert.Error(t, err)
 	assert.Equal(t, "forced remove connection ban error", err.Error())
+
+	connectionBansOutput, err := dsp.ConnectionBans(context.Background(), &dispatcher.ConnectionBansInput{EntityID: "someID"})
+	assert.NoError(t, err)
+	require.NotNil(t, connectionBansOutput)
+	require.Len(t, connectionBansOutput.ConnectionBans, 1)
+	assert.False(t, connectionBansOutput.HasMore)
+	ban := connectionBansOutput.ConnectionBans[0]
+	assert.Equal(t, "123123", ban.Reference)
+	assert.Equal(t, "someUser", ban.User)
+	assert.Equal(t, &dispatcher.RemoveConnectionBanInput{
+		Reference: "123123",
+		EntityID:  "someID",
+		Others:    []string{"someOtherID"},
+		Meta: dispatcher.RemoveConnectionBanMeta{
+			User: "anotherUser",
+		},
+	}, ban.RemoveInput("someID", dispatcher.RemoveConnectionBanMeta{User: "anotherUser"}))
+
+	removeRecordsOutput, err := dsp.RemoveRecords(context.Background(), &dispatcher.RemoveRecordsInput{
+		RecordIDs: []string{"12345"},
+		Meta: &dispatcher.RemoveRecordsMeta{
+			User:   "someUser",
+			Reason: "someReason",
+		},
+	})
+	assert.NoError(t, err)
+	require.NotNil(t, removeRecordsOutput)
+	assert.True(t, removeRecordsOutput.Triggered)
+	assert.Equal(t, []string{"12345"}, removeRecordsOutput.RemovedRecords)
+	assert.Equal(t, []string{"abcd"}, removeRecordsOutput.AffectedEntityIDs)
+	assert.Empty(t, removeRecordsOutput.DeletedEntityIDs)
 }
 
 type testDispatcher struct {
@@ -147,6 +269,59 @@ func (d *testDispatcher) EntityByRecord(ctx context.Context, _ *dispatcher.Entit
 	}, nil
 }
 
+func (d *testDispatcher) Entities(_ context.Context, input *dispatcher.EntitiesInput) (*dispatcher.EntitiesOutput, error) {
+	results := make([]*dispatcher.EntityResult, len(input.IDs))
+	for i, id := range input.IDs {
+		results[i] = &dispatcher.EntityResult{ID: id}
+		switch id {
+		case testEntity.ID:
+			results[i].Entity = &testEntity
+		case "invalid":
+			msg := "invalid entity id"
+			results[i].Error = &msg
+		}
+	}
+	return &dispatcher.EntitiesOutput{
+		Results: results,
+	}, nil
+}
+
+func (d *testDispatcher) EntitiesByRecords(_ context.Context, input *dispatcher.EntitiesByRecordsInput) (*dispatcher.EntitiesOutput, error) {
+	results := make([]*dispatcher.EntityResult, len(input.IDs))
+	for i, id := range input.IDs {
+		results[i] = &dispatcher.EntityResult{
+			ID:     id,
+			Entity: &testEntity,
+		}
+	}
+	return &dispatcher.EntitiesOutput{
+		Results: results,
+	}, nil
+}
+
+func (d *testDispatcher) EntityHistory(_ context.Context, input *dispatcher.EntityHistoryInput) (*dispatcher.EntityHistoryOutput, error) {
+	return &dispatcher.EntityHistoryOutput{
+		Changes: []*dispatcher.EntityHistoryChange{
+			{
+				Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				Type:      dispatcher.EntityHistorySubmit,
+				EntityIDs: []string{input.EntityID},
+				RecordIDs: []string{"12345", "67890"},
+			},
+			{
+				Timestamp: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+				Type:      dispatcher.EntityHistoryDisassemble,
+				EntityIDs: []string{input.EntityID},
+				RecordIDs: []string{"67890"},
+				DisassembleMeta: &dispatcher.DisassembleMeta{
+					User:   "someUser",
+					Reason: "someReason",
+				},
+			},
+		},
+	}, nil
+}
+
 func (d *testDispatcher) Search(_ context.Context, _ *dispatcher.SearchInput) (*dispatcher.SearchOutput, error) {
 	return &dispatcher.SearchOutput{
 		Entities: []*api.Entity{
@@ -155,10 +330,19 @@ func (d *testDispatcher) Search(_ context.Context, _ *dispatcher.SearchInput) (*
 	}, nil
 }
 
-func (d *testDispatcher) Submit(_ context.Context, _ *dispatcher.SubmitInput) (*dispatcher.SubmitOutput, error) {
-	return &dispatcher.SubmitOutput{
-		RecordsAdded: 1,
-	}, nil
+func (d *testDispatcher) Submit(_ context.Context, input *dispatcher.SubmitInput) (*dispatcher.SubmitOutput, error) {
+	jobID := "job-1"
+	output := &dispatcher.SubmitOutput{
+		RecordsAdded: len(input.Records),
+		JobID:        &jobID,
+	}
+	for _, record := range input.Records {
+		output.Records = append(output.Records, &dispatcher.SubmitRecordResult{
+			ID:     record.ID,
+			Status: dispatcher.SubmitRecordAccepted,
+		})
+	}
+	return output, nil
 }
 
 func (d *testDispatcher) SubmitWithPreview(ctx context.Context, input *dispatcher.SubmitWithPreviewInput) (*dispatcher.SubmitWithPreviewOutput, error) {
@@ -169,12 +353,109 @@ func (d *testDispatcher) SubmitWithPreview(ctx context.Context, input *dispatche
 	}, nil
 }
 
-func (d *testDispatcher) Disassemble(_ context.Context, _ *dispatcher.DisassembleInput) (*dispatcher.DisassembleOutput, error) {
-	return &dispatcher.DisassembleOutput{
-		Triggered: true,
-	}, nil
+func (d *testDispatcher) Disassemble(_ context.Context, input *dispatcher.DisassembleInput) (*dispatcher.DisassembleOutput, error) {
+	output := &dispatcher.DisassembleOutput{
+		Triggered:      true,
+		RemovedEdges:   api.Edges{"abc:0:def:0:R1:100"},
+		RemovedRecords: input.RecordIDs,
+		EntityIDs:      []string{testEntity.ID},
+	}
+	if input.CreateConnectionBan {
+		reference := "123123"
+		output.ConnectionBanReference = &reference
+	}
+	if input.Features != nil {
+		entities, err := input.Features.ProjectAll([]*api.Entity{&testEntity})
+		if err != nil {
+			return nil, err
+		}
+		output.Entities = entities
+	}
+	return output, nil
 }
 
 func (d *testDispatcher) RemoveConnectionBan(_ context.Context, _ *dispatcher.RemoveConnectionBanInput) error {
 	return fmt.Errorf("forced remove connection ban error")
 }
+
+func (d *testDispatcher) ConnectionBans(_ context.Context, input *dispatcher.ConnectionBansInput) (*dispatcher.ConnectionBansOutput, error) {
+	return &dispatcher.ConnectionBansOutput{
+		ConnectionBans: []*dispatcher.ConnectionBan{
+			{
+				Reference: "123123",
+				EntityIDs: []string{input.EntityID, "someOtherID"},
+				RecordIDs: []string{"12345", "67890"},
+				User:      "someUser",
+				Reason:    "someReason",
+				CreatedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}, nil
+}
+
+func (d *testDispatcher) RemoveRecords(_ context.Context, input *dispatcher.RemoveRecordsInput) (*dispatcher.RemoveRecordsOutput, error) {
+	return &dispatcher.RemoveRecordsOutput{
+		Triggered:         true,
+		RemovedRecords:    input.RecordIDs,
+		AffectedEntityIDs: []string{testEntity.ID},
+		DeletedEntityIDs:  []string{},
+	}, nil
+}
+
+func (d *testDispatcher) SubmitStatus(_ context.Context, input *dispatcher.SubmitStatusInput) (*dispatcher.SubmitStatusOutput, error) {
+	if input.JobID != "job-1" {
+		return nil, fmt.Errorf("unknown job %s", input.JobID)
+	}
+	return &dispatcher.SubmitStatusOutput{
+		Status:    dispatcher.SubmitJobCompleted,
+		Assembled: 1,
+		EntityIDs: []string{testEntity.ID},
+	}, nil
+}
+
+func (d *testDispatcher) Changes(_ context.Context, input *dispatcher.ChangesInput) (*dispatcher.ChangesOutput, error) {
+	changes := []*dispatcher.EntityChange{
+		{
+			Timestamp: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			Type:      dispatcher.EntityChangeCreated,
+			EntityIDs: []string{testEntity.ID},
+			RecordIDs: []string{"12345"},
+		},
+		{
+			Timestamp:         time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC),
+			Type:              dispatcher.EntityChangeMerged,
+			EntityIDs:         []string{testEntity.ID},
+			PreviousEntityIDs: []string{testEntity.ID, "efgh"},
+			RecordIDs:         []string{"67890"},
+		},
+	}
+	offset := 0
+	if input.Cursor != nil {
+		var err error
+		offset, err = strconv.Atoi(*input.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor %s", *input.Cursor)
+		}
+	}
+	end := len(changes)
+	if input.Limit != nil {
+		end = min(offset+*input.Limit, end)
+	}
+	next := strconv.Itoa(end)
+	return &dispatcher.ChangesOutput{
+		Changes:    changes[offset:end],
+		NextCursor: &next,
+		HasMore:    end < len(changes),
+	}, nil
+}
+
+func (d *testDispatcher) Link(_ context.Context, input *dispatcher.LinkInput) (*dispatcher.LinkOutput, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	return &dispatcher.LinkOutput{
+		Triggered: true,
+		Edges:     api.Edges{api.NewEdge(input.RecordIDs[0], input.RecordIDs[1], api.RuleManual, 100)},
+		EntityID:  testEntity.ID,
+	}, nil
+}
@@ -21,10 +21,18 @@ type provider struct {
 const (
 	entityMethod              = "/entity"
 	entityByRecordMethod      = "/entity-by-record"
+	entitiesMethod            = "/entities"
+	entitiesByRecordsMethod   = "/entities-by-records"
+	entityHistoryMethod       = "/entity-history"
 	submitMethod              = "/submit"
 	submitWithPreviewMethod   = "/submit-with-preview"
 	disassembleMethod         = "/disassemble"
 	removeConnectionBanMethod = "/removeconnectionban"
+	removeRecordsMethod       = "/remove-records"
+	connectionBansMethod      = "/connection-bans"
+	submitStatusMethod        = "/submit-status"
+	changesMethod             = "/changes"
+	linkMethod                = "/link"
 	searchMethod              = "/search"
 )
 
@@ -34,6 +42,12 @@ func (p *provider) Provide(method string) (plugin.RequestParameter, plugin.Invok
 		return &EntityInput{}, p.Entity, nil
 	case entityByRecordMethod:
 		return &EntityByRecordInput{}, p.EntityByRecord, nil
+	case entitiesMethod:
+		return &EntitiesInput{}, p.Entities, nil
+	case entitiesByRecordsMethod:
+		return &EntitiesByRecordsInput{}, p.EntitiesByRecords, nil
+	case entityHistoryMethod:
+		return &EntityHistoryInput{}, p.EntityHistory, nil
 	case submitMethod:
 		return &SubmitInput{}, p.Submit, nil
 	case submitWithPreviewMethod:
@@ -42,8 +56,18 @@ func (p *provider) Provide(method string) (plugin.RequestParameter, plugin.Invok
 		return &DisassembleInput{}, p.Disassemble, nil
 	case removeConnectionBanMethod:
 		return &RemoveConnectionBanInput{}, p.RemoveConnectionBan, nil
+	case connectionBansMethod:
+		return &ConnectionBansInput{}, p.ConnectionBans, nil
+	case removeRecordsMethod:
+		return &RemoveRecordsInput{}, p.RemoveRecords, nil
 	case searchMethod:
 		return &SearchInput{}, p.Search, nil
+	case submitStatusMethod:
+		return &SubmitStatusInput{}, p.SubmitStatus, nil
+	case changesMethod:
+		return &ChangesInput{}, p.Changes, nil
+	case linkMethod:
+		return &LinkInput{}, p.Link, nil
 	}
 	return nil, nil, fmt.Errorf("invalid method %v", method)
 }
@@ -56,6 +80,18 @@ func (p *provider) EntityByRecord(ctx context.Context, params plugin.RequestPara
 	return p.impl.EntityByRecord(ctx, params.(*EntityByRecordInput))
 }
 
+func (p *provider) Entities(ctx context.Context, params plugin.RequestParameter) (interface{}, error) {
+	return p.impl.Entities(ctx, params.(*EntitiesInput))
+}
+
+func (p *provider) EntitiesByRecords(ctx context.Context, params plugin.RequestParameter) (interface{}, error) {
+	return p.impl.EntitiesByRecords(ctx, params.(*EntitiesByRecordsInput))
+}
+
+func (p *provider) EntityHistory(ctx context.Context, params plugin.RequestParameter) (interface{}, error) {
+	return p.impl.EntityHistory(ctx, params.(*EntityHistoryInput))
+}
+
 func (p *provider) Submit(ctx context.Context, params plugin.RequestParameter) (interface{}, error) {
 	return p.impl.Submit(ctx, params.(*SubmitInput))
 }
@@ -72,6 +108,26 @@ func (p *provider) RemoveConnectionBan(ctx context.Context, params plugin.Reques
 	return nil, p.impl.RemoveConnectionBan(ctx, params.(*RemoveConnectionBanInput))
 }
 
+func (p *provider) ConnectionBans(ctx context.Context, params plugin.RequestParameter) (interface{}, error) {
+	return p.impl.ConnectionBans(ctx, params.(*ConnectionBansInput))
+}
+
+func (p *provider) RemoveRecords(ctx context.Context, params plugin.RequestParameter) (interface{}, error) {
+	return p.impl.RemoveRecords(ctx, params.(*RemoveRecordsInput))
+}
+
 func (p *provider) Search(ctx context.Context, params plugin.RequestParameter) (interface{}, error) {
 	return p.impl.Search(ctx, params.(*SearchInput))
 }
+
+func (p *provider) SubmitStatus(ctx context.Context, params plugin.RequestParameter) (interface{}, error) {
+	return p.impl.SubmitStatus(ctx, params.(*SubmitStatusInput))
+}
+
+func (p *provider) Changes(ctx context.Context, params plugin.RequestParameter) (interface{}, error) {
+	return p.impl.Changes(ctx, params.(*ChangesInput))
+}
+
+func (p *provider) Link(ctx context.Context, params plugin.RequestParameter) (interface{}, error) {
+	return p.impl.Link(ctx, params.(*LinkInput))
+}
@@ -42,6 +42,33 @@ func (p *proxy) EntityByRecord(ctx context.Context, input *EntityByRecordInput)
 	return response, nil
 }
 
+func (p *proxy) Entities(ctx context.Context, input *EntitiesInput) (*EntitiesOutput, error) {
+	response := &EntitiesOutput{}
+	err := p.client.Call(ctx, entitiesMethod, input, response)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (p *proxy) EntitiesByRecords(ctx context.Context, input *EntitiesByRecordsInput) (*EntitiesOutput, error) {
+	response := &EntitiesOutput{}
+	err := p.client.Call(ctx, entitiesByRecordsMethod, input, response)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (p *proxy) EntityHistory(ctx context.Context, input *EntityHistoryInput) (*EntityHistoryOutput, error) {
+	response := &EntityHistoryOutput{}
+	err := p.client.Call(ctx, entityHistoryMethod, input, response)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
 func (p *proxy) Submit(ctx context.Context, input *SubmitInput) (*SubmitOutput, error) {
 	response := &SubmitOutput{}
 	err := p.client.Call(ctx, submitMethod, input, response)
@@ -74,6 +101,24 @@ func (p *proxy) RemoveConnectionBan(ctx context.Context, input *RemoveConnection
 	return p.client.Call(ctx, removeConnectionBanMethod, input, response)
 }
 
+func (p *proxy) ConnectionBans(ctx context.Context, input *ConnectionBansInput) (*ConnectionBansOutput, error) {
+	response := &ConnectionBansOutput{}
+	err := p.client.Call(ctx, connectionBansMethod, input, response)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (p *proxy) RemoveRecords(ctx context.Context, input *RemoveRecordsInput) (*RemoveRecordsOutput, error) {
+	response := &RemoveRecordsOutput{}
+	err := p.client.Call(ctx, removeRecordsMethod, input, response)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
 func (p *proxy) Search(ctx context.Context, input *SearchInput) (*SearchOutput, error) {
 	response := &SearchOutput{}
 	err := p.client.Call(ctx, searchMethod, input, response)
@@ -82,3 +127,30 @@ func (p *proxy) Search(ctx context.Context, input *SearchInput) (*SearchOutput,
 	}
 	return response, nil
 }
+
+func (p *proxy) SubmitStatus(ctx context.Context, input *SubmitStatusInput) (*SubmitStatusOutput, error) {
+	response := &SubmitStatusOutput{}
+	err := p.client.Call(ctx, submitStatusMethod, input, response)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (p *proxy) Changes(ctx context.Context, input *ChangesInput) (*ChangesOutput, error) {
+	response := &ChangesOutput{}
+	err := p.client.Call(ctx, changesMethod, input, response)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (p *proxy) Link(ctx context.Context, input *LinkInput) (*LinkOutput, error) {
+	response := &LinkOutput{}
+	err := p.client.Call(ctx, linkMethod, input, response)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
@@ -0,0 +1,40 @@
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// SearchAll returns an iterator over all entities matching the search input.
+//
+// The pages are requested one after another using SearchOutput.NextCursor
+// while iterating. The provided input is not modified. If a search fails, the
+// error is yielded and the iteration stops.
+func SearchAll(ctx context.Context, d Dispatcher, input *SearchInput) iter.Seq2[*api.Entity, error] {
+	return func(yield func(*api.Entity, error) bool) {
+		current := *input
+		for {
+			output, err := d.Search(ctx, &current)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, entity := range output.Entities {
+				if !yield(entity, nil) {
+					return
+				}
+			}
+			if !output.HasMore {
+				return
+			}
+			if output.NextCursor == nil {
+				yield(nil, fmt.Errorf("search reported more entities without providing a cursor"))
+				return
+			}
+			current.Cursor = output.NextCursor
+		}
+	}
+}
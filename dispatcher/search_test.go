@@ -0,0 +1,76 @@
+package dispatcher_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "github.com/tilotech/tilores-plugin-api"
+	"github.com/tilotech/tilores-plugin-api/dispatcher"
+)
+
+type pagingDispatcher struct {
+	testDispatcher
+	entities []*api.Entity
+	noCursor bool
+	calls    int
+}
+
+func (d *pagingDispatcher) Search(_ context.Context, input *dispatcher.SearchInput) (*dispatcher.SearchOutput, error) {
+	d.calls++
+	offset := 0
+	if input.Cursor != nil {
+		var err error
+		offset, err = strconv.Atoi(*input.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+	}
+	end := min(offset+*input.PageSize, len(d.entities))
+	total := len(d.entities)
+	output := &dispatcher.SearchOutput{
+		Entities: d.entities[offset:end],
+		Total:    &total,
+		HasMore:  end < len(d.entities),
+	}
+	if output.HasMore && !d.noCursor {
+		next := strconv.Itoa(end)
+		output.NextCursor = &next
+	}
+	return output, nil
+}
+
+func TestSearchAll(t *testing.T) {
+	impl := &pagingDispatcher{
+		entities: []*api.Entity{{ID: "e1"}, {ID: "e2"}, {ID: "e3"}, {ID: "e4"}, {ID: "e5"}},
+	}
+	pageSize := 2
+	input := &dispatcher.SearchInput{PageSize: &pageSize}
+
+	ids := []string{}
+	for entity, err := range dispatcher.SearchAll(context.Background(), impl, input) {
+		require.NoError(t, err)
+		ids = append(ids, entity.ID)
+	}
+	assert.Equal(t, []string{"e1", "e2", "e3", "e4", "e5"}, ids)
+	assert.Equal(t, 3, impl.calls)
+	assert.Nil(t, input.Cursor)
+
+	impl.calls = 0
+	for entity := range dispatcher.SearchAll(context.Background(), impl, input) {
+		if entity.ID == "e3" {
+			break
+		}
+	}
+	assert.Equal(t, 2, impl.calls)
+
+	impl.noCursor = true
+	var lastErr error
+	for _, err := range dispatcher.SearchAll(context.Background(), impl, input) {
+		lastErr = err
+	}
+	assert.Error(t, lastErr)
+}
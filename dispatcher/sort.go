@@ -0,0 +1,160 @@
+package dispatcher
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	api "github.com/tilotech/tilores-plugin-api"
+	"github.com/tilotech/tilores-plugin-api/internal/values"
+)
+
+// SortEntities sorts the entities according to the criteria.
+//
+// The first criteria has the highest priority, all further criteria are only
+// used to break ties. The sort is stable, so that entities that are equal
+// according to all criteria keep their order.
+//
+// When sorting by SortEntityByDataPath, the smallest value of all records is
+// used for ascending and the largest value for descending order. Values are
+// normalized as for AggregateTerms. Numbers are sorted before times, times
+// before strings and strings before booleans. When sorting by
+// SortEntityByLatestSubmit or SortEntityByDataPath, entities without a value
+// are sorted last, independent of the direction.
+//
+// Nil criteria are ignored. An error is returned if any criteria uses an
+// unknown field or direction or an invalid path.
+func SortEntities(entities []*api.Entity, criteria ...*EntitySortCriteria) error {
+	criteria = slices.DeleteFunc(slices.Clone(criteria), func(c *EntitySortCriteria) bool {
+		return c == nil
+	})
+	keys := make([][]any, len(criteria))
+	descending := make([]bool, len(criteria))
+	for i, c := range criteria {
+		var err error
+		descending[i], err = isDescending(c)
+		if err != nil {
+			return err
+		}
+		keys[i], err = sortKeys(entities, c, descending[i])
+		if err != nil {
+			return err
+		}
+	}
+	index := make(map[*api.Entity]int, len(entities))
+	for i, entity := range entities {
+		index[entity] = i
+	}
+	slices.SortStableFunc(entities, func(a, b *api.Entity) int {
+		for i := range criteria {
+			if c := compareSortKeys(keys[i][index[a]], keys[i][index[b]], descending[i]); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return nil
+}
+
+func isDescending(criteria *EntitySortCriteria) (bool, error) {
+	if criteria.Direction != nil {
+		switch *criteria.Direction {
+		case SortEntityAscending:
+			return false, nil
+		case SortEntityDescending:
+			return true, nil
+		}
+		return false, fmt.Errorf("invalid sort direction %v", *criteria.Direction)
+	}
+	switch criteria.Field {
+	case SortEntityByID, SortEntityByDataPath:
+		return false, nil
+	}
+	return true, nil
+}
+
+func sortKeys(entities []*api.Entity, criteria *EntitySortCriteria, descending bool) ([]any, error) {
+	var key func(entity *api.Entity) any
+	switch criteria.Field {
+	case SortEntityByID:
+		key = func(entity *api.Entity) any { return entity.ID }
+	case SortEntityByHitScore:
+		key = func(entity *api.Entity) any { return entity.HitScore }
+	case SortEntityByScore:
+		key = func(entity *api.Entity) any { return entity.Score }
+	case SortEntityByConsistency:
+		key = func(entity *api.Entity) any { return entity.Consistency }
+	case SortEntityByRecordCount:
+		key = func(entity *api.Entity) any { return float64(len(entity.Records)) }
+	case SortEntityByLatestSubmit:
+		key = latestSubmit
+	case SortEntityByDataPath:
+		if criteria.Path == nil {
+			return nil, fmt.Errorf("missing path for sort field %v", criteria.Field)
+		}
+		path, err := api.ParsePath(*criteria.Path)
+		if err != nil {
+			return nil, err
+		}
+		key = func(entity *api.Entity) any { return dataPathKey(entity, path, descending) }
+	default:
+		return nil, fmt.Errorf("invalid sort field %v", criteria.Field)
+	}
+	keys := make([]any, len(entities))
+	for i, entity := range entities {
+		keys[i] = key(entity)
+	}
+	return keys, nil
+}
+
+func latestSubmit(entity *api.Entity) any {
+	var latest any
+	for _, record := range entity.Records {
+		if record.Meta == nil || record.Meta.SubmitTimestamp == nil {
+			continue
+		}
+		if latest == nil || record.Meta.SubmitTimestamp.After(latest.(time.Time)) {
+			latest = *record.Meta.SubmitTimestamp
+		}
+	}
+	return latest
+}
+
+func dataPathKey(entity *api.Entity, path api.Path, descending bool) any {
+	var key any
+	for _, record := range entity.Records {
+		for _, value := range path.Resolve(record.Data) {
+			value = values.Normalize(value)
+			if value == nil {
+				continue
+			}
+			c := compareSortKeys(value, key, false)
+			if key == nil || (c < 0 && !descending) || (c > 0 && descending) {
+				key = value
+			}
+		}
+	}
+	return key
+}
+
+// compareSortKeys compares two sort keys, always sorting nil keys last.
+func compareSortKeys(a, b any, descending bool) int {
+	if a == nil || b == nil {
+		return compareNil(a, b)
+	}
+	c := values.Compare(a, b)
+	if descending {
+		return -c
+	}
+	return c
+}
+
+func compareNil(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	}
+	return -1
+}
@@ -0,0 +1,143 @@
+package dispatcher_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "github.com/tilotech/tilores-plugin-api"
+	"github.com/tilotech/tilores-plugin-api/dispatcher"
+)
+
+func TestSortEntities(t *testing.T) {
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	newEntities := func() []*api.Entity {
+		return []*api.Entity{
+			{
+				ID:    "e1",
+				Score: 0.5,
+				Records: []*api.Record{
+					{ID: "r1", Data: map[string]any{"age": 30}, Meta: &api.RecordMeta{SubmitTimestamp: &t1}},
+				},
+			},
+			{
+				ID:          "e2",
+				Score:       0.9,
+				Consistency: 0.1,
+				Records: []*api.Record{
+					{ID: "r2", Data: map[string]any{"age": float64(20)}, Meta: &api.RecordMeta{SubmitTimestamp: &t1}},
+					{ID: "r3", Data: map[string]any{"age": uint8(50)}, Meta: &api.RecordMeta{SubmitTimestamp: &t2}},
+				},
+			},
+			{
+				ID:          "e3",
+				Score:       0.5,
+				Consistency: 0.2,
+				Records: []*api.Record{
+					{ID: "r4", Data: map[string]any{}},
+				},
+			},
+		}
+	}
+	asc := dispatcher.SortEntityAscending
+	desc := dispatcher.SortEntityDescending
+	path := "age"
+
+	cases := map[string]struct {
+		criteria []*dispatcher.EntitySortCriteria
+		expected []string
+	}{
+		"id": {
+			criteria: []*dispatcher.EntitySortCriteria{{Field: dispatcher.SortEntityByID, Direction: &desc}},
+			expected: []string{"e3", "e2", "e1"},
+		},
+		"score with tie breaker": {
+			criteria: []*dispatcher.EntitySortCriteria{
+				{Field: dispatcher.SortEntityByScore},
+				{Field: dispatcher.SortEntityByConsistency},
+			},
+			expected: []string{"e2", "e3", "e1"},
+		},
+		"score ascending keeps order for ties": {
+			criteria: []*dispatcher.EntitySortCriteria{{Field: dispatcher.SortEntityByScore, Direction: &asc}},
+			expected: []string{"e1", "e3", "e2"},
+		},
+		"record count": {
+			criteria: []*dispatcher.EntitySortCriteria{{Field: dispatcher.SortEntityByRecordCount}},
+			expected: []string{"e2", "e1", "e3"},
+		},
+		"latest submit": {
+			criteria: []*dispatcher.EntitySortCriteria{{Field: dispatcher.SortEntityByLatestSubmit}},
+			expected: []string{"e2", "e1", "e3"},
+		},
+		"latest submit ascending": {
+			criteria: []*dispatcher.EntitySortCriteria{{Field: dispatcher.SortEntityByLatestSubmit, Direction: &asc}},
+			expected: []string{"e1", "e2", "e3"},
+		},
+		"data path ascending": {
+			criteria: []*dispatcher.EntitySortCriteria{{Field: dispatcher.SortEntityByDataPath, Path: &path}},
+			expected: []string{"e2", "e1", "e3"},
+		},
+		"data path descending": {
+			criteria: []*dispatcher.EntitySortCriteria{{Field: dispatcher.SortEntityByDataPath, Path: &path, Direction: &desc}},
+			expected: []string{"e2", "e1", "e3"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			entities := newEntities()
+			err := dispatcher.SortEntities(entities, c.criteria...)
+			require.NoError(t, err)
+			ids := make([]string, len(entities))
+			for i, entity := range entities {
+				ids[i] = entity.ID
+			}
+			assert.Equal(t, c.expected, ids)
+		})
+	}
+}
+
+func TestSortEntitiesNilCriteria(t *testing.T) {
+	entities := []*api.Entity{{ID: "e2"}, {ID: "e1"}}
+
+	assert.NotPanics(t, func() {
+		require.NoError(t, dispatcher.SortEntities(entities, nil))
+		assert.Equal(t, []*api.Entity{{ID: "e2"}, {ID: "e1"}}, entities)
+
+		byID := &dispatcher.EntitySortCriteria{Field: dispatcher.SortEntityByID}
+		require.NoError(t, dispatcher.SortEntities(entities, nil, byID))
+		assert.Equal(t, []*api.Entity{{ID: "e1"}, {ID: "e2"}}, entities)
+	})
+}
+
+func TestSortEntitiesInvalidCriteria(t *testing.T) {
+	invalidPath := "age..x"
+	invalidDirection := dispatcher.EntitySortDirection("UP")
+
+	for name, criteria := range map[string]*dispatcher.EntitySortCriteria{
+		"unknown field":     {Field: "unknown"},
+		"missing path":      {Field: dispatcher.SortEntityByDataPath},
+		"invalid path":      {Field: dispatcher.SortEntityByDataPath, Path: &invalidPath},
+		"invalid direction": {Field: dispatcher.SortEntityByID, Direction: &invalidDirection},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, dispatcher.SortEntities([]*api.Entity{{ID: "e1"}}, criteria))
+		})
+	}
+}
+
+func TestSearchInputSortCriteria(t *testing.T) {
+	input := &dispatcher.SearchInput{}
+	assert.Empty(t, input.SortCriteria())
+
+	first := &dispatcher.EntitySortCriteria{Field: dispatcher.SortEntityByScore}
+	second := &dispatcher.EntitySortCriteria{Field: dispatcher.SortEntityByID}
+	input = &dispatcher.SearchInput{
+		Sort:     first,
+		ThenSort: []*dispatcher.EntitySortCriteria{nil, second},
+	}
+	assert.Equal(t, []*dispatcher.EntitySortCriteria{first, second}, input.SortCriteria())
+}
@@ -0,0 +1,64 @@
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// Rejected returns the results of all rejected records.
+func (o *SubmitOutput) Rejected() []*SubmitRecordResult {
+	rejected := []*SubmitRecordResult{}
+	for _, result := range o.Records {
+		if result.Status == SubmitRecordRejected {
+			rejected = append(rejected, result)
+		}
+	}
+	return rejected
+}
+
+// RejectedRecords returns the records from the given list that were rejected,
+// e.g. the records of the original SubmitInput, so that they can be retried
+// or handled separately.
+func (o *SubmitOutput) RejectedRecords(records []*api.Record) []*api.Record {
+	ids := map[string]struct{}{}
+	for _, result := range o.Rejected() {
+		ids[result.ID] = struct{}{}
+	}
+	rejected := []*api.Record{}
+	for _, record := range records {
+		if _, ok := ids[record.ID]; ok {
+			rejected = append(rejected, record)
+		}
+	}
+	return rejected
+}
+
+// WaitForSubmit polls SubmitStatus in the given interval until the job is no
+// longer pending and returns its final status.
+//
+// It stops with the context error when the context is done before. An error
+// is returned if the interval is not positive.
+func WaitForSubmit(ctx context.Context, d Dispatcher, jobID string, interval time.Duration) (*SubmitStatusOutput, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid poll interval %v", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		output, err := d.SubmitStatus(ctx, &SubmitStatusInput{JobID: jobID})
+		if err != nil {
+			return nil, err
+		}
+		if output.Status != SubmitJobPending {
+			return output, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package dispatcher_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "github.com/tilotech/tilores-plugin-api"
+	"github.com/tilotech/tilores-plugin-api/dispatcher"
+)
+
+func TestSubmitOutputRejected(t *testing.T) {
+	reason := "missing required field"
+	records := []*api.Record{{ID: "r1"}, {ID: "r2"}, {ID: "r3"}, {ID: "r4"}}
+	output := &dispatcher.SubmitOutput{
+		RecordsAdded: 1,
+		Records: []*dispatcher.SubmitRecordResult{
+			{ID: "r1", Status: dispatcher.SubmitRecordAccepted},
+			{ID: "r2", Status: dispatcher.SubmitRecordRejected, Reason: &reason},
+			{ID: "r3", Status: dispatcher.SubmitRecordUnchanged},
+			{ID: "r4", Status: dispatcher.SubmitRecordRejected, Reason: &reason},
+		},
+	}
+
+	assert.Equal(t, []*dispatcher.SubmitRecordResult{output.Records[1], output.Records[3]}, output.Rejected())
+	assert.Equal(t, []*api.Record{records[1], records[3]}, output.RejectedRecords(records))
+
+	output = &dispatcher.SubmitOutput{RecordsAdded: 4}
+	assert.Empty(t, output.Rejected())
+	assert.Empty(t, output.RejectedRecords(records))
+}
+
+type pollingDispatcher struct {
+	testDispatcher
+	pendingCalls int
+	calls        int
+}
+
+func (d *pollingDispatcher) SubmitStatus(_ context.Context, input *dispatcher.SubmitStatusInput) (*dispatcher.SubmitStatusOutput, error) {
+	d.calls++
+	if input.JobID != "job-1" {
+		return nil, fmt.Errorf("unknown job %s", input.JobID)
+	}
+	if d.calls <= d.pendingCalls {
+		return &dispatcher.SubmitStatusOutput{Status: dispatcher.SubmitJobPending, Pending: 1}, nil
+	}
+	return &dispatcher.SubmitStatusOutput{Status: dispatcher.SubmitJobFailed, Failed: 1}, nil
+}
+
+func TestWaitForSubmit(t *testing.T) {
+	d := &pollingDispatcher{pendingCalls: 2}
+	output, err := dispatcher.WaitForSubmit(context.Background(), d, "job-1", time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, dispatcher.SubmitJobFailed, output.Status)
+	assert.Equal(t, 3, d.calls)
+
+	_, err = dispatcher.WaitForSubmit(context.Background(), &pollingDispatcher{}, "unknown", time.Millisecond)
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = dispatcher.WaitForSubmit(ctx, &pollingDispatcher{pendingCalls: 1000}, "job-1", time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	d = &pollingDispatcher{}
+	_, err = dispatcher.WaitForSubmit(context.Background(), d, "job-1", 0)
+	assert.Error(t, err)
+	assert.Equal(t, 0, d.calls)
+}
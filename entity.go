@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -25,8 +26,145 @@ type Entity struct {
 //
 // Edges displayed to the user omit the version information, resulting in the
 // following form: <id>:<id>:<rule>
+//
+// Colons and backslashes within <id> are escaped, see EscapeRecordID.
 type Edges []string
 
+// Parse parses all edge strings using ParseEdgeStrict.
+//
+// An error is returned for the first edge that cannot be parsed.
+func (e Edges) Parse() ([]Edge, error) {
+	edges := make([]Edge, len(e))
+	for i, edge := range e {
+		parsed, err := ParseEdgeStrict(edge)
+		if err != nil {
+			return nil, err
+		}
+		edges[i] = parsed
+	}
+	return edges, nil
+}
+
+// Edge is the parsed form of a single entry in Edges.
+//
+// Edge implements encoding.TextMarshaler and encoding.TextUnmarshaler and is
+// therefore represented as an edge string in JSON.
+type Edge struct {
+	A     RecordID
+	B     RecordID
+	Rule  string
+	Score uint8
+}
+
+// ErrInvalidEdge is returned when an edge cannot be parsed or encoded.
+var ErrInvalidEdge = errors.New("invalid edge")
+
+const (
+	// RuleStatic is the rule of edges that were not created by one of the
+	// configured matching rules.
+	RuleStatic = "STATIC"
+	// RuleManual is the rule of edges that were created by a person using
+	// Link.
+	RuleManual = "MANUAL"
+)
+
+// ParseEdgeStrict parses an edge string into an Edge.
+//
+// It supports the same formats as ParseEdge, but returns an error instead of
+// guessing or panicking if the edge does not follow any of these formats, e.g.
+// because of an invalid version or score or because of additional colons.
+//
+// The returned error wraps ErrInvalidEdge and, if applicable, the error
+// returned by ParseRecordIDStrict.
+func ParseEdgeStrict(edge string) (Edge, error) {
+	return parseEdge(edge, false)
+}
+
+// ParseEdgeLegacy parses an edge string that was created before escaping was
+// introduced.
+//
+// It works like ParseEdgeStrict, but neither respects nor reverts escaped
+// colons, see ParseRecordIDLegacy.
+func ParseEdgeLegacy(edge string) (Edge, error) {
+	return parseEdge(edge, true)
+}
+
+func parseEdge(edge string, legacy bool) (Edge, error) {
+	var parts []string
+	if legacy {
+		parts = strings.Split(edge, ":")
+	} else {
+		parts = splitRecordID(edge, -1)
+	}
+	var a, b, rule string
+	score := uint64(100)
+	switch len(parts) {
+	case 3:
+		a, b, rule = parts[0], parts[1], parts[2]
+	case 5, 6:
+		a = fmt.Sprintf("%v:%v", parts[0], parts[1])
+		b = fmt.Sprintf("%v:%v", parts[2], parts[3])
+		rule = parts[4]
+		if len(parts) == 6 {
+			var err error
+			score, err = strconv.ParseUint(parts[5], 10, 8)
+			if err != nil || score > 100 {
+				return Edge{}, fmt.Errorf("%w %q: score must be between 0 and 100", ErrInvalidEdge, edge)
+			}
+		}
+	default:
+		return Edge{}, fmt.Errorf("%w %q: unexpected number of components", ErrInvalidEdge, edge)
+	}
+	ridA, err := parseRecordID(a, legacy)
+	if err != nil {
+		return Edge{}, fmt.Errorf("%w %q: %w", ErrInvalidEdge, edge, err)
+	}
+	ridB, err := parseRecordID(b, legacy)
+	if err != nil {
+		return Edge{}, fmt.Errorf("%w %q: %w", ErrInvalidEdge, edge, err)
+	}
+	if rule == "" {
+		return Edge{}, fmt.Errorf("%w %q: empty rule", ErrInvalidEdge, edge)
+	}
+	return Edge{
+		A:     ridA,
+		B:     ridB,
+		Rule:  rule,
+		Score: uint8(score),
+	}, nil
+}
+
+// String returns the edge string as created by NewEdgeWithVersions.
+func (e Edge) String() string {
+	return NewEdgeWithVersions(e.A.ID, e.A.Version, e.B.ID, e.B.Version, e.Rule, e.Score)
+}
+
+// MarshalText returns the edge string as created by NewEdgeWithVersions.
+func (e Edge) MarshalText() ([]byte, error) {
+	for _, id := range []RecordID{e.A, e.B} {
+		if id.ID == "" {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidEdge, ErrEmptyRecordID)
+		}
+		if id.Version < 0 {
+			return nil, fmt.Errorf("%w: %w: %v", ErrInvalidEdge, ErrNegativeRecordVersion, id.Version)
+		}
+	}
+	if e.Rule == "" || strings.Contains(e.Rule, ":") || e.Score > 100 {
+		return nil, fmt.Errorf("%w: invalid rule %q or score %v", ErrInvalidEdge, e.Rule, e.Score)
+	}
+	return []byte(e.String()), nil
+}
+
+// UnmarshalText parses the edge string using ParseEdgeStrict.
+func (e *Edge) UnmarshalText(text []byte) error {
+	edge, err := ParseEdgeStrict(string(text))
+	if err != nil {
+		return err
+	}
+	*e = edge
+	return nil
+}
+
 // Duplicates represents all record duplicates within the entity
 //
 // Duplicates are typically in the form <group>:<id>:<version> with <group>
@@ -62,6 +200,22 @@ func (h Hits) IDs() []string {
 	return ids
 }
 
+// IDsStrict returns the record ids of the hits.
+//
+// Other than IDs, it returns an error instead of panicking if a key is not a
+// valid record ID.
+func (h Hits) IDsStrict() ([]string, error) {
+	ids := make([]string, 0, len(h))
+	for k := range h {
+		rid, err := ParseRecordIDStrict(k)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, rid.ID)
+	}
+	return ids, nil
+}
+
 // ParseEdge parses an edge string into its components.
 //
 // Return values are: id1, id2 and rule
@@ -73,8 +227,10 @@ func (h Hits) IDs() []string {
 // If the version information is not provided, then version 0 is assumed.
 // If the score is not provided, then score 100 is assumed.
 // The behavior for other formats is undefined.
+//
+// Use ParseEdgeStrict for edges that are not guaranteed to be well-formed.
 func ParseEdge(edge string) (string, string, string, uint8) {
-	parts := strings.SplitN(edge, ":", 6)
+	parts := splitRecordID(edge, 6)
 	var id1, id2, rule string
 	var v1, v2 int
 	var score uint8
@@ -122,7 +278,7 @@ func NewEdge(id1, id2, rule string, score uint8) string {
 //
 // The resulting string will be in the format: <id>:<version>:<id>:<version>:<rule>:<score>
 func NewEdgeWithVersions(id1 string, v1 int, id2 string, v2 int, rule string, score uint8) string {
-	return fmt.Sprintf("%v:%v:%v:%v:%v:%v", id1, v1, id2, v2, rule, score)
+	return fmt.Sprintf("%v:%v:%v:%v:%v:%v", EscapeRecordID(id1), v1, EscapeRecordID(id2), v2, rule, score)
 }
 
 // ParseDuplicateKey parses the key (original) of a duplicate into its components.
@@ -139,15 +295,15 @@ func NewEdgeWithVersions(id1 string, v1 int, id2 string, v2 int, rule string, sc
 // The behavior for other formats is undefined, especially the format
 // <id>:<version> is not defined!
 func ParseDuplicateKey(key string) (string, string) {
-	parts := strings.SplitN(key, ":", 3)
+	parts := splitRecordID(key, 3)
 	var group, id string
 	var v int
 	switch len(parts) {
 	case 1:
-		id = parts[0]
+		id, _ = ParseRecordID(parts[0])
 	case 2:
 		group = parts[0]
-		id = parts[1]
+		id, _ = ParseRecordID(parts[1])
 	default:
 		group = parts[0]
 		id, v = ParseRecordID(fmt.Sprintf("%v:%v", parts[1], parts[2]))
@@ -155,6 +311,31 @@ func ParseDuplicateKey(key string) (string, string) {
 	return NewRecordID(id, v), group
 }
 
+// ParseDuplicateKeyStrict parses the key (original) of a duplicate into its
+// components.
+//
+// It supports the same formats as ParseDuplicateKey, but returns an error
+// instead of panicking if the key does not contain a valid record ID.
+func ParseDuplicateKeyStrict(key string) (string, string, error) {
+	parts := splitRecordID(key, 3)
+	var group, id string
+	switch len(parts) {
+	case 1:
+		id = parts[0]
+	case 2:
+		group = parts[0]
+		id = parts[1]
+	default:
+		group = parts[0]
+		id = fmt.Sprintf("%v:%v", parts[1], parts[2])
+	}
+	rid, err := ParseRecordIDStrict(id)
+	if err != nil {
+		return "", "", err
+	}
+	return rid.String(), group, nil
+}
+
 // NewDuplicateKey returns a new duplicate key string with the provided id and group.
 //
 // id must be in the format: <id>:<version> or <id>
@@ -164,7 +345,7 @@ func ParseDuplicateKey(key string) (string, string) {
 // The resulting string will be in the format: <group>:<id>:<version>
 func NewDuplicateKey(id, group string) string {
 	rid, v := ParseRecordID(id)
-	return fmt.Sprintf("%v:%v:%v", group, rid, v)
+	return fmt.Sprintf("%v:%v:%v", group, EscapeRecordID(rid), v)
 }
 
 // ParseRecordID parses the record id into its components.
@@ -174,16 +355,22 @@ func NewDuplicateKey(id, group string) string {
 // The recordID must be in the format: <id>:<version> or <id>
 // If only <id> is used, then the version is assumed to be 0.
 // The behavior for other formats is undefined.
+//
+// The returned id is unescaped, see EscapeRecordID.
+//
+// ParseRecordID panics if the version is not an integer. Use
+// ParseRecordIDStrict for record IDs that are not guaranteed to be valid.
 func ParseRecordID(recordID string) (string, int) {
-	parts := strings.SplitN(recordID, ":", 2)
+	parts := splitRecordID(recordID, 2)
+	id := UnescapeRecordID(parts[0])
 	if len(parts) == 1 {
-		return parts[0], 0
+		return id, 0
 	}
 	version, err := strconv.Atoi(parts[1])
 	if err != nil {
 		panic(err)
 	}
-	return parts[0], version
+	return id, version
 }
 
 // ParseRecordIDWithOptionalVersion parses the record id into its components.
@@ -192,16 +379,116 @@ func ParseRecordID(recordID string) (string, int) {
 //
 // The recordID must be in the format: <id>:<version> or <id>
 // The behavior for other formats is undefined.
+//
+// ParseRecordIDWithOptionalVersion panics if the version is not an integer.
+// Use ParseRecordIDWithOptionalVersionStrict for record IDs that are not
+// guaranteed to be valid.
 func ParseRecordIDWithOptionalVersion(recordID string) (string, *int) {
-	parts := strings.SplitN(recordID, ":", 2)
+	parts := splitRecordID(recordID, 2)
+	id := UnescapeRecordID(parts[0])
 	if len(parts) == 1 {
-		return parts[0], nil
+		return id, nil
 	}
 	version, err := strconv.Atoi(parts[1])
 	if err != nil {
 		panic(err)
 	}
-	return parts[0], &version
+	return id, &version
+}
+
+// RecordID is the parsed form of a record ID as defined by NewRecordID.
+type RecordID struct {
+	ID      string
+	Version int
+}
+
+var (
+	// ErrEmptyRecordID is returned when the id part of a record ID is empty.
+	ErrEmptyRecordID = errors.New("empty record id")
+	// ErrInvalidRecordVersion is returned when the version part of a record ID
+	// is not an integer.
+	ErrInvalidRecordVersion = errors.New("invalid record version")
+	// ErrNegativeRecordVersion is returned when the version part of a record ID
+	// is a negative integer.
+	ErrNegativeRecordVersion = errors.New("negative record version")
+)
+
+// ParseRecordIDStrict parses the record id into its components.
+//
+// The recordID must be in the format: <id>:<version> or <id>
+// If only <id> is used, then the version is assumed to be 0.
+//
+// Other than ParseRecordID, it returns an error instead of panicking. The
+// error wraps one of ErrEmptyRecordID, ErrInvalidRecordVersion or
+// ErrNegativeRecordVersion.
+func ParseRecordIDStrict(recordID string) (RecordID, error) {
+	return parseRecordID(recordID, false)
+}
+
+// ParseRecordIDLegacy parses a record id that was created before escaping was
+// introduced.
+//
+// It works like ParseRecordIDStrict, but splits at the first colon and returns
+// the id part unchanged, even if it contains backslashes.
+func ParseRecordIDLegacy(recordID string) (RecordID, error) {
+	return parseRecordID(recordID, true)
+}
+
+func parseRecordID(recordID string, legacy bool) (RecordID, error) {
+	id, version, err := parseRecordIDWithOptionalVersion(recordID, legacy)
+	if err != nil {
+		return RecordID{}, err
+	}
+	if version == nil {
+		return RecordID{ID: id}, nil
+	}
+	return RecordID{ID: id, Version: *version}, nil
+}
+
+// ParseRecordIDWithOptionalVersionStrict parses the record id into its
+// components.
+//
+// Return values are: id, version, error
+//
+// The recordID must be in the format: <id>:<version> or <id>
+//
+// Other than ParseRecordIDWithOptionalVersion, it returns an error instead of
+// panicking. The error wraps one of ErrEmptyRecordID, ErrInvalidRecordVersion
+// or ErrNegativeRecordVersion.
+func ParseRecordIDWithOptionalVersionStrict(recordID string) (string, *int, error) {
+	return parseRecordIDWithOptionalVersion(recordID, false)
+}
+
+func parseRecordIDWithOptionalVersion(recordID string, legacy bool) (string, *int, error) {
+	var parts []string
+	if legacy {
+		parts = strings.SplitN(recordID, ":", 2)
+	} else {
+		parts = splitRecordID(recordID, 2)
+	}
+	if parts[0] == "" {
+		return "", nil, fmt.Errorf("%w: %q", ErrEmptyRecordID, recordID)
+	}
+	id := parts[0]
+	if !legacy {
+		id = UnescapeRecordID(id)
+	}
+	if len(parts) == 1 {
+		return id, nil, nil
+	}
+	version, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %q", ErrInvalidRecordVersion, recordID)
+	}
+	if version < 0 {
+		return "", nil, fmt.Errorf("%w: %q", ErrNegativeRecordVersion, recordID)
+	}
+	return id, &version, nil
+}
+
+// String returns the record ID as created by NewRecordID.
+func (r RecordID) String() string {
+	return NewRecordID(r.ID, r.Version)
 }
 
 // NewRecordID returns a new record id string with the provided id and
@@ -210,11 +497,13 @@ func ParseRecordIDWithOptionalVersion(recordID string) (string, *int) {
 // The resulting string will be in the format: <id>:<version> or <id>
 // If the provided version is equal to 0, then the output will not include the
 // version.
+//
+// The id is escaped using EscapeRecordID.
 func NewRecordID(id string, version int) string {
 	if version == 0 {
-		return id
+		return EscapeRecordID(id)
 	}
-	return fmt.Sprintf("%v:%v", id, version)
+	return fmt.Sprintf("%v:%v", EscapeRecordID(id), version)
 }
 
 // NewRecordIDWithVersion returns a new record id string with the provided id
@@ -226,5 +515,173 @@ func NewRecordID(id string, version int) string {
 //
 // Most cases should use NewRecordID.
 func NewRecordIDWithVersion(id string, version int) string {
+	return fmt.Sprintf("%v:%v", EscapeRecordID(id), version)
+}
+
+// NewRecordIDLegacy returns a new record id string without escaping the id.
+//
+// It works like NewRecordID before escaping was introduced and must only be
+// used where the result is read by code that does not support escaping.
+// ids containing colons cannot be parsed correctly afterwards.
+func NewRecordIDLegacy(id string, version int) string {
+	if version == 0 {
+		return id
+	}
 	return fmt.Sprintf("%v:%v", id, version)
 }
+
+// NewEdgeLegacy returns a new edge string without escaping the ids.
+//
+// id1 and id2 must be the plain record ID without a version. Like
+// NewRecordIDLegacy, it must only be used where the result is read by code
+// that does not support escaping.
+func NewEdgeLegacy(id1 string, v1 int, id2 string, v2 int, rule string, score uint8) string {
+	return fmt.Sprintf("%v:%v:%v:%v:%v:%v", id1, v1, id2, v2, rule, score)
+}
+
+// EscapeRecordID escapes the id part of a record ID.
+//
+// Colons are escaped as "\:" and backslashes as "\\". All other characters
+// remain unchanged. Record IDs created by NewRecordID, NewEdge or
+// NewDuplicateKey are already escaped.
+//
+// Data written before escaping was introduced is still read correctly by all
+// parsers, because backslashes that are not followed by a colon or another
+// backslash are kept as is, see UnescapeRecordID. Only ids that contain a
+// backslash followed by a colon or another backslash are read differently.
+// Use ParseRecordIDLegacy and ParseEdgeLegacy for such data.
+func EscapeRecordID(id string) string {
+	if !strings.ContainsAny(id, `\:`) {
+		return id
+	}
+	escaped := strings.Builder{}
+	for i := 0; i < len(id); i++ {
+		if id[i] == ':' || id[i] == '\\' {
+			escaped.WriteByte('\\')
+		}
+		escaped.WriteByte(id[i])
+	}
+	return escaped.String()
+}
+
+// UnescapeRecordID reverts the escaping done by EscapeRecordID.
+//
+// A backslash that is not followed by a colon or another backslash is not an
+// escape sequence and is kept as is, e.g. in ids written before escaping was
+// introduced.
+func UnescapeRecordID(id string) string {
+	if !strings.Contains(id, `\`) {
+		return id
+	}
+	unescaped := strings.Builder{}
+	for i := 0; i < len(id); i++ {
+		if id[i] == '\\' && i+1 < len(id) && (id[i+1] == ':' || id[i+1] == '\\') {
+			i++
+		}
+		unescaped.WriteByte(id[i])
+	}
+	return unescaped.String()
+}
+
+// splitRecordID works like strings.SplitN with a colon as separator, but
+// ignores escaped colons.
+func splitRecordID(s string, n int) []string {
+	parts := []string{}
+	start := 0
+	for i := 0; i < len(s) && (n < 0 || len(parts) < n-1); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ':':
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// SubEntity returns a new entity that only contains the given records.
+//
+// Edges are only kept if both records are part of the sub entity. Duplicates
+// and hits are only kept for records that are part of the sub entity. The
+// records are kept in their original order. The ID and all scores are copied
+// from the original entity.
+//
+// Edges, duplicates and hits that cannot be parsed are kept unchanged, hence
+// they are part of every sub entity.
+//
+// recordIDs must be the plain IDs of the records without versions.
+func (e *Entity) SubEntity(recordIDs []string) *Entity {
+	keep := make(map[string]bool, len(recordIDs))
+	for _, id := range recordIDs {
+		keep[id] = true
+	}
+	return e.subEntity(keep)
+}
+
+// subEntity returns a new entity with the records in keep.
+func (e *Entity) subEntity(keep map[string]bool) *Entity {
+	sub := &Entity{
+		ID:          e.ID,
+		Records:     []*Record{},
+		Edges:       Edges{},
+		Duplicates:  Duplicates{},
+		Hits:        Hits{},
+		Consistency: e.Consistency,
+		Score:       e.Score,
+		HitScore:    e.HitScore,
+	}
+	for _, record := range e.Records {
+		if keep[record.ID] {
+			sub.Records = append(sub.Records, record)
+		}
+	}
+	for _, edge := range e.Edges {
+		parsed, err := ParseEdgeStrict(edge)
+		if err != nil || keep[parsed.A.ID] && keep[parsed.B.ID] {
+			sub.Edges = append(sub.Edges, edge)
+		}
+	}
+	for key, duplicates := range e.Duplicates {
+		id, _, err := ParseDuplicateKeyStrict(key)
+		if err == nil {
+			var rid RecordID
+			rid, err = ParseRecordIDStrict(id)
+			id = rid.ID
+		}
+		if err != nil || keep[id] {
+			sub.Duplicates[key] = duplicates
+		}
+	}
+	for key, rules := range e.Hits {
+		rid, err := ParseRecordIDStrict(key)
+		if err != nil || keep[rid.ID] {
+			sub.Hits[key] = rules
+		}
+	}
+	return sub
+}
+
+// PageRecords returns a new entity that only contains the records within the
+// given page.
+//
+// The page starts at offset (0-based) and contains at most limit records. A
+// negative limit returns all records after the offset. The records keep their
+// order, hence implementations must return the records in a stable order.
+//
+// Edges are only kept if both of their records are part of the page,
+// duplicates and hits are only kept for the records of the page. Hence edges
+// between records of different pages are not returned on any page. The ID and
+// all scores are copied from the original entity.
+func (e *Entity) PageRecords(offset, limit int) *Entity {
+	offset = min(max(offset, 0), len(e.Records))
+	end := len(e.Records)
+	if limit >= 0 && limit < end-offset {
+		end = offset + limit
+	}
+	page := make(map[string]bool, end-offset)
+	for _, record := range e.Records[offset:end] {
+		page[record.ID] = true
+	}
+	return e.subEntity(page)
+}
@@ -1,6 +1,8 @@
 package api_test
 
 import (
+	"encoding/json"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -20,6 +22,22 @@ func TestHitsIDs(t *testing.T) {
 	assert.ElementsMatch(t, []string{"id1", "id2", "id3"}, actual)
 }
 
+func TestHitsIDsStrict(t *testing.T) {
+	hits := api.Hits{
+		"id1":   []string{"R1", "R2"},
+		"id2:0": []string{"R1"},
+		"id3:1": []string{"R1"},
+	}
+
+	actual, err := hits.IDsStrict()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"id1", "id2", "id3"}, actual)
+
+	hits["id4:x"] = []string{"R1"}
+	_, err = hits.IDsStrict()
+	assert.ErrorIs(t, err, api.ErrInvalidRecordVersion)
+}
+
 func TestParseEdge(t *testing.T) {
 	edge := "id1:0:id2:9:R1:56"
 	a, b, rule, score := api.ParseEdge(edge)
@@ -50,6 +68,83 @@ func TestParseEdge(t *testing.T) {
 	assert.Equal(t, uint8(0), score)
 }
 
+func TestParseEdgeStrict(t *testing.T) {
+	cases := map[string]struct {
+		input       string
+		expected    api.Edge
+		expectError bool
+	}{
+		"with versions and score": {
+			input:    "id1:0:id2:9:R1:56",
+			expected: api.Edge{A: api.RecordID{ID: "id1"}, B: api.RecordID{ID: "id2", Version: 9}, Rule: "R1", Score: 56},
+		},
+		"with versions": {
+			input:    "id1:0:id2:9:R1",
+			expected: api.Edge{A: api.RecordID{ID: "id1"}, B: api.RecordID{ID: "id2", Version: 9}, Rule: "R1", Score: 100},
+		},
+		"without versions": {
+			input:    "id1:id2:R1",
+			expected: api.Edge{A: api.RecordID{ID: "id1"}, B: api.RecordID{ID: "id2"}, Rule: "R1", Score: 100},
+		},
+		"invalid score":      {input: "id1:0:id2:9:R1:invalid", expectError: true},
+		"score out of range": {input: "id1:0:id2:9:R1:101", expectError: true},
+		"invalid version":    {input: "id1:x:id2:9:R1", expectError: true},
+		"negative version":   {input: "id1:-1:id2:9:R1", expectError: true},
+		"version with score": {input: "id1:0:id2:1.5:R1:56", expectError: true},
+		"too many colons":    {input: "crm:1:0:id2:9:R1:56", expectError: true},
+		"too few colons":     {input: "id1:id2", expectError: true},
+		"empty id":           {input: ":id2:R1", expectError: true},
+		"empty rule":         {input: "id1:id2:", expectError: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := api.ParseEdgeStrict(c.input)
+			if c.expectError {
+				require.ErrorIs(t, err, api.ErrInvalidEdge)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestEdgeJSON(t *testing.T) {
+	edges := []api.Edge{}
+	err := json.Unmarshal([]byte(`["id1:0:id2:9:R1:56", "id1:id3:STATIC"]`), &edges)
+	require.NoError(t, err)
+	assert.Equal(t, []api.Edge{
+		{A: api.RecordID{ID: "id1"}, B: api.RecordID{ID: "id2", Version: 9}, Rule: "R1", Score: 56},
+		{A: api.RecordID{ID: "id1"}, B: api.RecordID{ID: "id3"}, Rule: "STATIC", Score: 100},
+	}, edges)
+
+	actual, err := json.Marshal(edges)
+	require.NoError(t, err)
+	assert.JSONEq(t, `["id1:0:id2:9:R1:56", "id1:0:id3:0:STATIC:100"]`, string(actual))
+
+	err = json.Unmarshal([]byte(`["id1:0:id2:9:R1:invalid"]`), &edges)
+	assert.ErrorIs(t, err, api.ErrInvalidEdge)
+
+	_, err = json.Marshal(api.Edge{A: api.RecordID{ID: "id1", Version: -1}, B: api.RecordID{ID: "id2"}, Rule: "R1"})
+	assert.ErrorIs(t, err, api.ErrInvalidEdge)
+
+	_, err = json.Marshal(api.Edge{A: api.RecordID{ID: "id1"}, B: api.RecordID{ID: "id2"}, Rule: "R1", Score: 101})
+	assert.ErrorIs(t, err, api.ErrInvalidEdge)
+}
+
+func TestEdgesParse(t *testing.T) {
+	edges, err := api.Edges{"id1:0:id2:9:R1:56", "id2:id3:R2"}.Parse()
+	require.NoError(t, err)
+	assert.Equal(t, []api.Edge{
+		{A: api.RecordID{ID: "id1"}, B: api.RecordID{ID: "id2", Version: 9}, Rule: "R1", Score: 56},
+		{A: api.RecordID{ID: "id2"}, B: api.RecordID{ID: "id3"}, Rule: "R2", Score: 100},
+	}, edges)
+
+	_, err = api.Edges{"id1:0:id2:9:R1:56", "invalid"}.Parse()
+	assert.ErrorIs(t, err, api.ErrInvalidEdge)
+}
+
 func TestParseDuplicateKey(t *testing.T) {
 	duplicate := ":id:9"
 	id, group := api.ParseDuplicateKey(duplicate)
@@ -113,6 +208,86 @@ func TestParseRecordIDWithOptionalVersion(t *testing.T) {
 	assert.Equal(t, 1, *version)
 }
 
+func TestParseDuplicateKeyStrict(t *testing.T) {
+	cases := map[string]struct {
+		input         string
+		expectedID    string
+		expectedGroup string
+		expectedError error
+	}{
+		"group, id and version": {input: "default:id:9", expectedID: "id:9", expectedGroup: "default"},
+		"empty group":           {input: ":id:0", expectedID: "id"},
+		"group and id":          {input: "default:id", expectedID: "id", expectedGroup: "default"},
+		"id only":               {input: "id", expectedID: "id"},
+		"invalid version":       {input: "default:id:x", expectedError: api.ErrInvalidRecordVersion},
+		"negative version":      {input: "default:id:-2", expectedError: api.ErrNegativeRecordVersion},
+		"empty id":              {input: "default:", expectedError: api.ErrEmptyRecordID},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			id, group, err := api.ParseDuplicateKeyStrict(c.input)
+			if c.expectedError != nil {
+				require.ErrorIs(t, err, c.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expectedID, id)
+			assert.Equal(t, c.expectedGroup, group)
+		})
+	}
+}
+
+func TestParseRecordIDStrict(t *testing.T) {
+	cases := map[string]struct {
+		input         string
+		expected      api.RecordID
+		expectedError error
+	}{
+		"with version":     {input: "foo:9", expected: api.RecordID{ID: "foo", Version: 9}},
+		"with version 0":   {input: "foo:0", expected: api.RecordID{ID: "foo"}},
+		"without version":  {input: "foo", expected: api.RecordID{ID: "foo"}},
+		"empty":            {input: "", expectedError: api.ErrEmptyRecordID},
+		"empty id":         {input: ":1", expectedError: api.ErrEmptyRecordID},
+		"invalid version":  {input: "foo:bar", expectedError: api.ErrInvalidRecordVersion},
+		"empty version":    {input: "foo:", expectedError: api.ErrInvalidRecordVersion},
+		"negative version": {input: "foo:-1", expectedError: api.ErrNegativeRecordVersion},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := api.ParseRecordIDStrict(c.input)
+			if c.expectedError != nil {
+				require.ErrorIs(t, err, c.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestParseRecordIDWithOptionalVersionStrict(t *testing.T) {
+	id, version, err := api.ParseRecordIDWithOptionalVersionStrict("foo")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", id)
+	assert.Nil(t, version)
+
+	id, version, err = api.ParseRecordIDWithOptionalVersionStrict("foo:0")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", id)
+	require.NotNil(t, version)
+	assert.Equal(t, 0, *version)
+
+	_, _, err = api.ParseRecordIDWithOptionalVersionStrict("foo:bar")
+	assert.ErrorIs(t, err, api.ErrInvalidRecordVersion)
+}
+
+func TestRecordIDString(t *testing.T) {
+	assert.Equal(t, "foo:9", api.RecordID{ID: "foo", Version: 9}.String())
+	assert.Equal(t, "foo", api.RecordID{ID: "foo"}.String())
+}
+
 func TestNewEdge(t *testing.T) {
 	actual := api.NewEdge("foo:1", "bar:2", "R1", 100)
 	assert.Equal(t, "foo:1:bar:2:R1:100", actual)
@@ -156,3 +331,180 @@ func TestNewRecordIDWithVersion(t *testing.T) {
 	actual = api.NewRecordIDWithVersion("foo", 0)
 	assert.Equal(t, "foo:0", actual)
 }
+
+func TestRecordIDEscaping(t *testing.T) {
+	assert.Equal(t, `crm\:12345`, api.EscapeRecordID("crm:12345"))
+	assert.Equal(t, `a\\b\:c`, api.EscapeRecordID(`a\b:c`))
+	assert.Equal(t, "plain", api.EscapeRecordID("plain"))
+
+	assert.Equal(t, `a\b:c`, api.UnescapeRecordID(`a\\b\:c`))
+	assert.Equal(t, `a\b`, api.UnescapeRecordID(`a\b`))
+	assert.Equal(t, `a\`, api.UnescapeRecordID(`a\`))
+
+	rid := api.NewRecordID("crm:12345", 2)
+	assert.Equal(t, `crm\:12345:2`, rid)
+	id, version := api.ParseRecordID(rid)
+	assert.Equal(t, "crm:12345", id)
+	assert.Equal(t, 2, version)
+
+	parsed, err := api.ParseRecordIDStrict(api.NewRecordID("crm:12345", 0))
+	require.NoError(t, err)
+	assert.Equal(t, api.RecordID{ID: "crm:12345"}, parsed)
+
+	parsed, err = api.ParseRecordIDStrict(`crm\x:1`)
+	require.NoError(t, err)
+	assert.Equal(t, api.RecordID{ID: `crm\x`, Version: 1}, parsed)
+
+	edge := api.NewEdge(api.NewRecordID("crm:1", 3), "erp:2", "R1", 80)
+	assert.Equal(t, `crm\:1:3:erp:2:R1:80`, edge)
+	a, b, rule, score := api.ParseEdge(edge)
+	assert.Equal(t, api.NewRecordID("crm:1", 3), a)
+	assert.Equal(t, "erp:2", b)
+	assert.Equal(t, "R1", rule)
+	assert.Equal(t, uint8(80), score)
+
+	edge = api.NewEdgeWithVersions("crm:1", 3, "erp:2", 0, "R1", 80)
+	parsedEdge, err := api.ParseEdgeStrict(edge)
+	require.NoError(t, err)
+	assert.Equal(t, api.Edge{A: api.RecordID{ID: "crm:1", Version: 3}, B: api.RecordID{ID: "erp:2"}, Rule: "R1", Score: 80}, parsedEdge)
+
+	key := api.NewDuplicateKey(api.NewRecordID("crm:1", 3), "default")
+	assert.Equal(t, `default:crm\:1:3`, key)
+	dupID, group := api.ParseDuplicateKey(key)
+	assert.Equal(t, api.NewRecordID("crm:1", 3), dupID)
+	assert.Equal(t, "default", group)
+
+	dupID, group = api.ParseDuplicateKey(`default:crm\:1`)
+	assert.Equal(t, `crm\:1`, dupID)
+	assert.Equal(t, "default", group)
+
+	hits := api.Hits{api.NewRecordID("crm:1", 3): []string{"R1"}}
+	assert.Equal(t, []string{"crm:1"}, hits.IDs())
+}
+
+func TestRecordIDEscapingCompatibility(t *testing.T) {
+	// unescaped data without colons is read unchanged
+	id, version := api.ParseRecordID(`legacy\id:2`)
+	assert.Equal(t, `legacy\id`, id)
+	assert.Equal(t, 2, version)
+
+	parsed, err := api.ParseRecordIDStrict(`legacy\id:2`)
+	require.NoError(t, err)
+	assert.Equal(t, api.RecordID{ID: `legacy\id`, Version: 2}, parsed)
+
+	edge, err := api.ParseEdgeStrict(`a\b:0:c:0:R1:100`)
+	require.NoError(t, err)
+	assert.Equal(t, api.Edge{A: api.RecordID{ID: `a\b`}, B: api.RecordID{ID: "c"}, Rule: "R1", Score: 100}, edge)
+
+	var unmarshalled api.Edge
+	require.NoError(t, unmarshalled.UnmarshalText([]byte(`a\b:0:c:0:R1:100`)))
+	assert.Equal(t, edge, unmarshalled)
+
+	assert.Equal(t, "crm:12345", api.NewRecordIDLegacy("crm:12345", 0))
+	assert.Equal(t, `legacy\id:2`, api.NewRecordIDLegacy(`legacy\id`, 2))
+
+	parsed, err = api.ParseRecordIDLegacy(`legacy\id:2`)
+	require.NoError(t, err)
+	assert.Equal(t, api.RecordID{ID: `legacy\id`, Version: 2}, parsed)
+
+	parsed, err = api.ParseRecordIDLegacy(`a\:1`)
+	require.NoError(t, err)
+	assert.Equal(t, api.RecordID{ID: `a\`, Version: 1}, parsed)
+
+	_, err = api.ParseRecordIDLegacy(`a:x`)
+	assert.ErrorIs(t, err, api.ErrInvalidRecordVersion)
+
+	assert.Equal(t, `a\:1:b\:0:R1:80`, api.NewEdgeLegacy(`a\`, 1, `b\`, 0, "R1", 80))
+
+	edge, err = api.ParseEdgeLegacy(`a\:1:b\:0:R1:80`)
+	require.NoError(t, err)
+	assert.Equal(t, api.Edge{A: api.RecordID{ID: `a\`, Version: 1}, B: api.RecordID{ID: `b\`}, Rule: "R1", Score: 80}, edge)
+
+	_, err = api.ParseEdgeLegacy(`a:1:b:0:R1:80:extra`)
+	assert.ErrorIs(t, err, api.ErrInvalidEdge)
+}
+
+func TestSubEntity(t *testing.T) {
+	entity := &api.Entity{
+		ID:      "e1",
+		Records: []*api.Record{{ID: "r1"}, {ID: "r2"}, {ID: "r3"}},
+		Edges:   api.Edges{"r1:0:r2:1:R1:100", "r2:1:r3:0:R1:100"},
+		Duplicates: api.Duplicates{
+			":r2:1": []string{"d2"},
+			":r3:0": []string{"d3"},
+		},
+		Hits:     api.Hits{"r1": []string{"R1"}, "r3": []string{"R1"}},
+		HitScore: 0.5,
+	}
+
+	actual := entity.SubEntity([]string{"r1", "r2"})
+	assert.Equal(t, &api.Entity{
+		ID:         "e1",
+		Records:    []*api.Record{{ID: "r1"}, {ID: "r2"}},
+		Edges:      api.Edges{"r1:0:r2:1:R1:100"},
+		Duplicates: api.Duplicates{":r2:1": []string{"d2"}},
+		Hits:       api.Hits{"r1": []string{"R1"}},
+		HitScore:   0.5,
+	}, actual)
+}
+
+func TestSubEntityInvalidKeys(t *testing.T) {
+	entity := &api.Entity{
+		ID:         "e1",
+		Records:    []*api.Record{{ID: "r1"}},
+		Edges:      api.Edges{"r1:x:r2:0:R1:100", "r1:r1:STATIC"},
+		Duplicates: api.Duplicates{"g:r1:x": []string{"d1"}, ":r1:0": []string{"d2"}},
+		Hits:       api.Hits{"x:y": []string{"R1"}, "r1": []string{"R1"}},
+	}
+
+	assert.NotPanics(t, func() {
+		actual := entity.SubEntity([]string{"r1"})
+		assert.Equal(t, entity.Edges, actual.Edges)
+		assert.Equal(t, entity.Duplicates, actual.Duplicates)
+		assert.Equal(t, entity.Hits, actual.Hits)
+
+		actual = entity.SubEntity([]string{})
+		assert.Equal(t, api.Edges{"r1:x:r2:0:R1:100"}, actual.Edges)
+		assert.Equal(t, api.Duplicates{"g:r1:x": []string{"d1"}}, actual.Duplicates)
+		assert.Equal(t, api.Hits{"x:y": []string{"R1"}}, actual.Hits)
+	})
+}
+
+func TestPageRecords(t *testing.T) {
+	entity := &api.Entity{
+		ID:      "e1",
+		Records: []*api.Record{{ID: "r1"}, {ID: "r2"}, {ID: "r3"}, {ID: "r4"}},
+		Edges:   api.Edges{"r1:r2:R1", "r2:r3:R1", "r3:r4:R1"},
+		Duplicates: api.Duplicates{
+			":r1:0": []string{"d1"},
+			":r3:0": []string{"d3"},
+		},
+		Hits:  api.Hits{"r2": []string{"R1"}, "r4": []string{"R1"}},
+		Score: 0.5,
+	}
+
+	actual := entity.PageRecords(1, 2)
+	assert.Equal(t, &api.Entity{
+		ID:         "e1",
+		Records:    []*api.Record{{ID: "r2"}, {ID: "r3"}},
+		Edges:      api.Edges{"r2:r3:R1"},
+		Duplicates: api.Duplicates{":r3:0": []string{"d3"}},
+		Hits:       api.Hits{"r2": []string{"R1"}},
+		Score:      0.5,
+	}, actual)
+
+	actual = entity.PageRecords(2, -1)
+	assert.Equal(t, []*api.Record{{ID: "r3"}, {ID: "r4"}}, actual.Records)
+	assert.Equal(t, api.Edges{"r3:r4:R1"}, actual.Edges)
+
+	actual = entity.PageRecords(3, -1)
+	assert.Equal(t, []*api.Record{{ID: "r4"}}, actual.Records)
+	assert.Empty(t, actual.Edges)
+
+	actual = entity.PageRecords(1, math.MaxInt)
+	assert.Equal(t, []*api.Record{{ID: "r2"}, {ID: "r3"}, {ID: "r4"}}, actual.Records)
+
+	actual = entity.PageRecords(10, 2)
+	assert.Empty(t, actual.Records)
+	assert.Empty(t, actual.Edges)
+}
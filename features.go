@@ -5,6 +5,9 @@ package api
 //
 // A feature is only considered inactive in case it was explicitly set to false.
 // The feature is considered active in case of nil, missing value and explicit true.
+//
+// RecordFields limits the data of each record to the given paths (see
+// Path). If it is nil, then the full data is returned.
 type Features struct {
 	EntityConsistency *bool `json:"entityConsistency"`
 	EntityDuplicates  *bool `json:"entityDuplicates"`
@@ -13,4 +16,158 @@ type Features struct {
 	EntityHitScore    *bool `json:"entityHitScore"`
 	EntityRecords     *bool `json:"entityRecords"`
 	EntityScore       *bool `json:"entityScore"`
+
+	RecordFields *[]string `json:"recordFields"`
+}
+
+// Validate returns an error if any of the RecordFields is not a valid path.
+func (f Features) Validate() error {
+	_, err := f.recordFieldPaths()
+	return err
+}
+
+func (f Features) recordFieldPaths() ([]Path, error) {
+	if f.RecordFields == nil {
+		return nil, nil
+	}
+	paths := make([]Path, 0, len(*f.RecordFields))
+	for _, field := range *f.RecordFields {
+		path, err := ParsePath(field)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// ProjectRecord returns a copy of the record whose data only contains the
+// RecordFields.
+//
+// If RecordFields is nil, then the record is returned unchanged. Otherwise,
+// the data is limited using SelectPaths. An error is returned if any of the
+// RecordFields is not a valid path.
+func (f Features) ProjectRecord(record *Record) (*Record, error) {
+	if f.RecordFields == nil || record == nil {
+		return record, nil
+	}
+	paths, err := f.recordFieldPaths()
+	if err != nil {
+		return nil, err
+	}
+	return projectRecord(record, paths), nil
+}
+
+func projectRecord(record *Record, paths []Path) *Record {
+	return &Record{
+		ID:   record.ID,
+		Data: SelectPaths(record.Data, paths...),
+		Meta: record.Meta,
+	}
+}
+
+// IsEntityConsistencyActive returns whether the entity consistency is active.
+func (f Features) IsEntityConsistencyActive() bool {
+	return isActive(f.EntityConsistency)
+}
+
+// IsEntityDuplicatesActive returns whether the entity duplicates are active.
+func (f Features) IsEntityDuplicatesActive() bool {
+	return isActive(f.EntityDuplicates)
+}
+
+// IsEntityEdgesActive returns whether the entity edges are active.
+func (f Features) IsEntityEdgesActive() bool {
+	return isActive(f.EntityEdges)
+}
+
+// IsEntityHitsActive returns whether the entity hits are active.
+func (f Features) IsEntityHitsActive() bool {
+	return isActive(f.EntityHits)
+}
+
+// IsEntityHitScoreActive returns whether the entity hit score is active.
+func (f Features) IsEntityHitScoreActive() bool {
+	return isActive(f.EntityHitScore)
+}
+
+// IsEntityRecordsActive returns whether the entity records are active.
+func (f Features) IsEntityRecordsActive() bool {
+	return isActive(f.EntityRecords)
+}
+
+// IsEntityScoreActive returns whether the entity score is active.
+func (f Features) IsEntityScoreActive() bool {
+	return isActive(f.EntityScore)
+}
+
+func isActive(feature *bool) bool {
+	return feature == nil || *feature
+}
+
+// Project returns a copy of the entity without the inactive features.
+//
+// Inactive lists and maps are set to nil, inactive scores are set to 0. If
+// RecordFields is set, then the records data is limited as in ProjectRecord.
+// An error is returned if any of the RecordFields is not a valid path.
+//
+// The provided entity is not modified. A nil entity returns nil.
+func (f Features) Project(entity *Entity) (*Entity, error) {
+	paths, err := f.recordFieldPaths()
+	if err != nil {
+		return nil, err
+	}
+	return f.project(entity, paths), nil
+}
+
+func (f Features) project(entity *Entity, paths []Path) *Entity {
+	if entity == nil {
+		return nil
+	}
+	projected := *entity
+	if !f.IsEntityRecordsActive() {
+		projected.Records = nil
+	} else if f.RecordFields != nil {
+		projected.Records = make([]*Record, len(entity.Records))
+		for i, record := range entity.Records {
+			projected.Records[i] = projectRecord(record, paths)
+		}
+	}
+	if !f.IsEntityEdgesActive() {
+		projected.Edges = nil
+	}
+	if !f.IsEntityDuplicatesActive() {
+		projected.Duplicates = nil
+	}
+	if !f.IsEntityHitsActive() {
+		projected.Hits = nil
+	}
+	if !f.IsEntityConsistencyActive() {
+		projected.Consistency = 0
+	}
+	if !f.IsEntityScoreActive() {
+		projected.Score = 0
+	}
+	if !f.IsEntityHitScoreActive() {
+		projected.HitScore = 0
+	}
+	return &projected
+}
+
+// ProjectAll returns copies of all entities without the inactive features.
+//
+// See Project for details.
+func (f Features) ProjectAll(entities []*Entity) ([]*Entity, error) {
+	paths, err := f.recordFieldPaths()
+	if err != nil {
+		return nil, err
+	}
+	if entities == nil {
+		return nil, nil
+	}
+	projected := make([]*Entity, len(entities))
+	for i, entity := range entities {
+		projected[i] = f.project(entity, paths)
+	}
+	return projected, nil
 }
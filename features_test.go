@@ -0,0 +1,124 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestFeaturesIsActive(t *testing.T) {
+	features := api.Features{
+		EntityEdges:   pointer(false),
+		EntityRecords: pointer(true),
+	}
+	assert.True(t, features.IsEntityConsistencyActive())
+	assert.True(t, features.IsEntityDuplicatesActive())
+	assert.False(t, features.IsEntityEdgesActive())
+	assert.True(t, features.IsEntityHitsActive())
+	assert.True(t, features.IsEntityHitScoreActive())
+	assert.True(t, features.IsEntityRecordsActive())
+	assert.True(t, features.IsEntityScoreActive())
+}
+
+func TestFeaturesProject(t *testing.T) {
+	entity := &api.Entity{
+		ID:          "e1",
+		Records:     []*api.Record{{ID: "r1"}, {ID: "r2"}},
+		Edges:       api.Edges{"r1:r2:R1"},
+		Duplicates:  api.Duplicates{"r1": []string{"d1"}},
+		Hits:        api.Hits{"r1": []string{"R1"}},
+		Consistency: 0.9,
+		Score:       0.8,
+		HitScore:    0.7,
+	}
+
+	actual, err := api.Features{}.Project(entity)
+	require.NoError(t, err)
+	assert.Equal(t, entity, actual)
+	assert.NotSame(t, entity, actual)
+
+	inactive := pointer(false)
+	actual, err = api.Features{
+		EntityConsistency: inactive,
+		EntityDuplicates:  inactive,
+		EntityEdges:       inactive,
+		EntityHits:        inactive,
+		EntityHitScore:    inactive,
+		EntityRecords:     inactive,
+		EntityScore:       inactive,
+	}.Project(entity)
+	require.NoError(t, err)
+	assert.Equal(t, &api.Entity{ID: "e1"}, actual)
+	assert.Len(t, entity.Records, 2)
+
+	actual, err = api.Features{EntityEdges: inactive, EntityScore: inactive}.Project(entity)
+	require.NoError(t, err)
+	assert.Equal(t, &api.Entity{
+		ID:          "e1",
+		Records:     entity.Records,
+		Duplicates:  entity.Duplicates,
+		Hits:        entity.Hits,
+		Consistency: 0.9,
+		HitScore:    0.7,
+	}, actual)
+
+	actual, err = api.Features{}.Project(nil)
+	require.NoError(t, err)
+	assert.Nil(t, actual)
+
+	all, err := api.Features{
+		EntityConsistency: inactive,
+		EntityDuplicates:  inactive,
+		EntityEdges:       inactive,
+		EntityHits:        inactive,
+		EntityHitScore:    inactive,
+		EntityRecords:     inactive,
+		EntityScore:       inactive,
+	}.ProjectAll([]*api.Entity{entity})
+	require.NoError(t, err)
+	assert.Equal(t, []*api.Entity{{ID: "e1"}}, all)
+}
+
+func TestFeaturesProjectRecord(t *testing.T) {
+	record := &api.Record{
+		ID: "r1",
+		Data: map[string]any{
+			"name":    map[string]any{"first": "Jane", "last": "Doe"},
+			"address": map[string]any{"zip": "12345", "city": "Berlin"},
+		},
+		Meta: &api.RecordMeta{Version: 2},
+	}
+
+	actual, err := api.Features{}.ProjectRecord(record)
+	require.NoError(t, err)
+	assert.Same(t, record, actual)
+
+	features := api.Features{RecordFields: &[]string{"name.last", "address.zip"}}
+	require.NoError(t, features.Validate())
+	actual, err = features.ProjectRecord(record)
+	require.NoError(t, err)
+	assert.Equal(t, &api.Record{
+		ID: "r1",
+		Data: map[string]any{
+			"name":    map[string]any{"last": "Doe"},
+			"address": map[string]any{"zip": "12345"},
+		},
+		Meta: record.Meta,
+	}, actual)
+
+	projected, err := features.Project(&api.Entity{ID: "e1", Records: []*api.Record{record}})
+	require.NoError(t, err)
+	assert.Equal(t, []*api.Record{actual}, projected.Records)
+	assert.Len(t, record.Data["name"], 2)
+
+	features = api.Features{RecordFields: &[]string{"name..last"}}
+	assert.Error(t, features.Validate())
+	_, err = features.ProjectRecord(record)
+	assert.Error(t, err)
+	_, err = features.Project(&api.Entity{ID: "e1", Records: []*api.Record{record}})
+	assert.Error(t, err)
+	_, err = features.ProjectAll([]*api.Entity{{ID: "e1"}})
+	assert.Error(t, err)
+}
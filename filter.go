@@ -1,10 +1,28 @@
 package api
 
-import "time"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tilotech/tilores-plugin-api/internal/values"
+)
 
 // FilterCondition defines the criterias that must be met when assuming a
 // different record presence during the search.
+//
+// All provided criterias must be met for the condition to match. Criterias
+// that are nil are ignored. The result is negated if Invert is true.
+//
+// String comparisons (Equals, StartsWith, EndsWith and LikeRegex) are case
+// insensitive unless CaseSensitive is true.
 type FilterCondition struct {
+	// Path references the values within the records data, see Path.
 	Path string
 
 	Equals any
@@ -27,3 +45,333 @@ type FilterCondition struct {
 	Invert        *bool
 	CaseSensitive *bool
 }
+
+// Matches returns whether the record meets the filter condition.
+//
+// The Path is resolved against the records data as described in Path. If the
+// path references multiple values, e.g. when using wildcards, then the
+// condition matches if at least one of the values meets all criterias. Values
+// that are nil or missing are considered null.
+//
+// Numeric comparisons accept numbers and strings that can be parsed as a
+// number. Time comparisons accept time.Time values and strings in RFC 3339
+// format. A value of a different type does not match.
+//
+// An error is returned if the Path is invalid or if LikeRegex is not a valid
+// regular expression.
+func (f *FilterCondition) Matches(record *Record) (bool, error) {
+	values, err := ResolvePath(record, f.Path)
+	if err != nil {
+		return false, err
+	}
+	matches := false
+	if len(values) == 0 {
+		matches, err = f.matchesValue(nil, false)
+	}
+	for _, value := range values {
+		matches, err = f.matchesValue(value, true)
+		if err != nil || matches {
+			break
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	if f.Invert != nil && *f.Invert {
+		return !matches, nil
+	}
+	return matches, nil
+}
+
+// MatchesAll returns whether the record meets all of the filter conditions.
+//
+// This is the behavior expected for the ConsiderRecords of the dispatcher
+// inputs. An empty list of conditions always matches.
+func MatchesAll(conditions []*FilterCondition, record *Record) (bool, error) {
+	for _, condition := range conditions {
+		matches, err := condition.Matches(record)
+		if err != nil || !matches {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// FilterExpression combines multiple FilterConditions using boolean logic.
+//
+// An expression is a tree whose leafs are FilterConditions. All of And, Or, Not
+// and Condition are optional. If more than one of them is set, then all of them
+// must match. An empty expression always matches.
+//
+// Example (in JSON) for "source is CRM or (source is ERP and submitted after X)":
+//
+//	{
+//	  "Or": [
+//	    {"Condition": {"Path": "source", "Equals": "CRM"}},
+//	    {"And": [
+//	      {"Condition": {"Path": "source", "Equals": "ERP"}},
+//	      {"Condition": {"Path": "submitted", "After": "2024-01-01T00:00:00Z"}}
+//	    ]}
+//	  ]
+//	}
+//
+// A plain list of FilterConditions, as used for ConsiderRecords, is also
+// accepted when unmarshalling JSON and is treated as if all conditions must
+// match. Expressions that can be represented as such a list will also be
+// marshalled as one.
+type FilterExpression struct {
+	And       []*FilterExpression
+	Or        []*FilterExpression
+	Not       *FilterExpression
+	Condition *FilterCondition
+}
+
+// NewFilterExpression returns a new filter expression that matches if all of
+// the provided conditions match.
+func NewFilterExpression(conditions ...*FilterCondition) *FilterExpression {
+	and := make([]*FilterExpression, len(conditions))
+	for i, condition := range conditions {
+		and[i] = &FilterExpression{Condition: condition}
+	}
+	return &FilterExpression{And: and}
+}
+
+// CombineFilters returns a filter expression that matches if all of the
+// conditions and the expression match.
+//
+// This combines the ConsiderRecords and ConsiderRecordsExpression of the
+// dispatcher inputs. If both are empty, then the returned expression always
+// matches.
+func CombineFilters(conditions []*FilterCondition, expression *FilterExpression) *FilterExpression {
+	if expression == nil {
+		return NewFilterExpression(conditions...)
+	}
+	if len(conditions) == 0 {
+		return expression
+	}
+	return FilterAnd(NewFilterExpression(conditions...), expression)
+}
+
+// FilterAnd returns a new filter expression that matches if all of the
+// provided expressions match.
+func FilterAnd(expressions ...*FilterExpression) *FilterExpression {
+	return &FilterExpression{And: expressions}
+}
+
+// FilterOr returns a new filter expression that matches if at least one of the
+// provided expressions match.
+func FilterOr(expressions ...*FilterExpression) *FilterExpression {
+	return &FilterExpression{Or: expressions}
+}
+
+// FilterNot returns a new filter expression that matches if the provided
+// expression does not match.
+func FilterNot(expression *FilterExpression) *FilterExpression {
+	return &FilterExpression{Not: expression}
+}
+
+// Matches returns whether the record meets the filter expression.
+//
+// A nil expression always matches.
+func (e *FilterExpression) Matches(record *Record) (bool, error) {
+	if e == nil {
+		return true, nil
+	}
+	if e.Condition != nil {
+		matches, err := e.Condition.Matches(record)
+		if err != nil || !matches {
+			return false, err
+		}
+	}
+	for _, and := range e.And {
+		matches, err := and.Matches(record)
+		if err != nil || !matches {
+			return false, err
+		}
+	}
+	if e.Not != nil {
+		matches, err := e.Not.Matches(record)
+		if err != nil || matches {
+			return false, err
+		}
+	}
+	if len(e.Or) == 0 {
+		return true, nil
+	}
+	for _, or := range e.Or {
+		matches, err := or.Matches(record)
+		if err != nil || matches {
+			return matches, err
+		}
+	}
+	return false, nil
+}
+
+// Flatten returns the list of conditions that must all match, if the
+// expression can be represented as such.
+//
+// The second return value is false if the expression uses Or or Not.
+func (e *FilterExpression) Flatten() ([]*FilterCondition, bool) {
+	conditions := []*FilterCondition{}
+	if e == nil {
+		return conditions, true
+	}
+	if len(e.Or) != 0 || e.Not != nil {
+		return nil, false
+	}
+	if e.Condition != nil {
+		conditions = append(conditions, e.Condition)
+	}
+	for _, and := range e.And {
+		sub, ok := and.Flatten()
+		if !ok {
+			return nil, false
+		}
+		conditions = append(conditions, sub...)
+	}
+	return conditions, true
+}
+
+type filterExpression FilterExpression
+
+// MarshalJSON returns the JSON representation of the expression.
+//
+// If possible, the expression is represented as a plain list of conditions.
+func (e *FilterExpression) MarshalJSON() ([]byte, error) {
+	if conditions, ok := e.Flatten(); ok {
+		return json.Marshal(conditions)
+	}
+	return json.Marshal((*filterExpression)(e))
+}
+
+// UnmarshalJSON parses the provided bytes and populates the FilterExpression.
+//
+// The bytes may either contain an expression object or a plain list of
+// conditions.
+func (e *FilterExpression) UnmarshalJSON(b []byte) error {
+	if !bytes.HasPrefix(bytes.TrimSpace(b), []byte("[")) {
+		return json.Unmarshal(b, (*filterExpression)(e))
+	}
+	conditions := []*FilterCondition{}
+	err := json.Unmarshal(b, &conditions)
+	if err != nil {
+		return err
+	}
+	*e = *NewFilterExpression(conditions...)
+	return nil
+}
+
+func (f *FilterCondition) matchesValue(value any, found bool) (bool, error) {
+	if f.IsNull != nil && *f.IsNull != (!found || value == nil) {
+		return false, nil
+	}
+	if f.Equals != nil && !f.equals(value) {
+		return false, nil
+	}
+	matches, err := f.matchesString(value)
+	if err != nil || !matches {
+		return false, err
+	}
+	return f.matchesNumber(value) && f.matchesTime(value), nil
+}
+
+func (f *FilterCondition) equals(value any) bool {
+	if a, ok := f.Equals.(string); ok {
+		if b, ok := value.(string); ok {
+			return f.compareString(a, b)
+		}
+	}
+	a, okA := values.Float(f.Equals)
+	b, okB := values.Float(value)
+	if okA && okB {
+		return a == b
+	}
+	return reflect.DeepEqual(f.Equals, value)
+}
+
+func (f *FilterCondition) matchesString(value any) (bool, error) {
+	if f.StartsWith == nil && f.EndsWith == nil && f.LikeRegex == nil {
+		return true, nil
+	}
+	s, ok := toString(value)
+	if !ok {
+		return false, nil
+	}
+	caseSensitive := f.CaseSensitive != nil && *f.CaseSensitive
+	if !caseSensitive {
+		s = strings.ToLower(s)
+	}
+	if f.StartsWith != nil && !strings.HasPrefix(s, f.lower(*f.StartsWith)) {
+		return false, nil
+	}
+	if f.EndsWith != nil && !strings.HasSuffix(s, f.lower(*f.EndsWith)) {
+		return false, nil
+	}
+	if f.LikeRegex == nil {
+		return true, nil
+	}
+	expr := *f.LikeRegex
+	if !caseSensitive {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return false, fmt.Errorf("invalid regular expression for path %v: %w", f.Path, err)
+	}
+	return re.MatchString(s), nil
+}
+
+func (f *FilterCondition) matchesNumber(value any) bool {
+	if f.LessThan == nil && f.LessEquals == nil && f.GreaterThan == nil && f.GreaterEquals == nil {
+		return true
+	}
+	n, ok := values.Float(value)
+	if !ok {
+		return false
+	}
+	return (f.LessThan == nil || n < *f.LessThan) &&
+		(f.LessEquals == nil || n <= *f.LessEquals) &&
+		(f.GreaterThan == nil || n > *f.GreaterThan) &&
+		(f.GreaterEquals == nil || n >= *f.GreaterEquals)
+}
+
+func (f *FilterCondition) matchesTime(value any) bool {
+	if f.After == nil && f.Since == nil && f.Before == nil && f.Until == nil {
+		return true
+	}
+	t, ok := values.Time(value)
+	if !ok {
+		return false
+	}
+	return (f.After == nil || t.After(*f.After)) &&
+		(f.Since == nil || !t.Before(*f.Since)) &&
+		(f.Before == nil || t.Before(*f.Before)) &&
+		(f.Until == nil || !t.After(*f.Until))
+}
+
+func (f *FilterCondition) compareString(a, b string) bool {
+	if f.CaseSensitive != nil && *f.CaseSensitive {
+		return a == b
+	}
+	return strings.EqualFold(a, b)
+}
+
+func (f *FilterCondition) lower(s string) string {
+	if f.CaseSensitive != nil && *f.CaseSensitive {
+		return s
+	}
+	return strings.ToLower(s)
+}
+
+func toString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	if n, ok := values.Float(value); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64), true
+	}
+	return "", false
+}
@@ -0,0 +1,309 @@
+package api_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestFilterConditionMatches(t *testing.T) {
+	record := &api.Record{
+		ID: "foo",
+		Data: map[string]any{
+			"source": "CRM",
+			"name": map[string]any{
+				"first": "Jane",
+				"last":  "Doe",
+			},
+			"age":       float64(42),
+			"weight":    "70.5",
+			"submitted": "2024-03-01T12:00:00Z",
+			"empty":     nil,
+			"emails": []any{
+				"jane@example.com",
+				"jane.doe@example.org",
+			},
+		},
+	}
+	march := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	february := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		condition   *api.FilterCondition
+		expected    bool
+		expectError bool
+	}{
+		"equals string": {
+			condition: &api.FilterCondition{Path: "source", Equals: "crm"},
+			expected:  true,
+		},
+		"equals string case sensitive": {
+			condition: &api.FilterCondition{Path: "source", Equals: "crm", CaseSensitive: pointer(true)},
+			expected:  false,
+		},
+		"equals number": {
+			condition: &api.FilterCondition{Path: "age", Equals: 42},
+			expected:  true,
+		},
+		"equals nested": {
+			condition: &api.FilterCondition{Path: "name.last", Equals: "Doe"},
+			expected:  true,
+		},
+		"equals missing": {
+			condition: &api.FilterCondition{Path: "name.middle", Equals: "Doe"},
+			expected:  false,
+		},
+		"wildcard any value": {
+			condition: &api.FilterCondition{Path: "emails[*]", EndsWith: pointer(".org")},
+			expected:  true,
+		},
+		"wildcard no value": {
+			condition: &api.FilterCondition{Path: "emails[*]", EndsWith: pointer(".net")},
+			expected:  false,
+		},
+		"invalid path": {
+			condition:   &api.FilterCondition{Path: "emails[", Equals: "foo"},
+			expectError: true,
+		},
+		"is null for nil value": {
+			condition: &api.FilterCondition{Path: "empty", IsNull: pointer(true)},
+			expected:  true,
+		},
+		"is null for missing value": {
+			condition: &api.FilterCondition{Path: "unknown", IsNull: pointer(true)},
+			expected:  true,
+		},
+		"is not null": {
+			condition: &api.FilterCondition{Path: "source", IsNull: pointer(false)},
+			expected:  true,
+		},
+		"starts with": {
+			condition: &api.FilterCondition{Path: "name.first", StartsWith: pointer("ja")},
+			expected:  true,
+		},
+		"starts with case sensitive": {
+			condition: &api.FilterCondition{Path: "name.first", StartsWith: pointer("ja"), CaseSensitive: pointer(true)},
+			expected:  false,
+		},
+		"ends with": {
+			condition: &api.FilterCondition{Path: "name.first", EndsWith: pointer("NE")},
+			expected:  true,
+		},
+		"like regex": {
+			condition: &api.FilterCondition{Path: "name.last", LikeRegex: pointer("^d.e$")},
+			expected:  true,
+		},
+		"invalid regex": {
+			condition:   &api.FilterCondition{Path: "name.last", LikeRegex: pointer("(")},
+			expectError: true,
+		},
+		"numeric range": {
+			condition: &api.FilterCondition{Path: "age", GreaterThan: pointer(41.0), LessEquals: pointer(42.0)},
+			expected:  true,
+		},
+		"numeric range excluded": {
+			condition: &api.FilterCondition{Path: "age", LessThan: pointer(42.0)},
+			expected:  false,
+		},
+		"numeric string": {
+			condition: &api.FilterCondition{Path: "weight", GreaterEquals: pointer(70.5)},
+			expected:  true,
+		},
+		"numeric on text": {
+			condition: &api.FilterCondition{Path: "source", GreaterEquals: pointer(0.0)},
+			expected:  false,
+		},
+		"time since": {
+			condition: &api.FilterCondition{Path: "submitted", Since: &march, Until: &march},
+			expected:  true,
+		},
+		"time after": {
+			condition: &api.FilterCondition{Path: "submitted", After: &march},
+			expected:  false,
+		},
+		"time before": {
+			condition: &api.FilterCondition{Path: "submitted", After: &february, Before: &march},
+			expected:  false,
+		},
+		"invert": {
+			condition: &api.FilterCondition{Path: "source", Equals: "ERP", Invert: pointer(true)},
+			expected:  true,
+		},
+		"no criteria": {
+			condition: &api.FilterCondition{Path: "source"},
+			expected:  true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := c.condition.Matches(record)
+			if c.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestMatchesAll(t *testing.T) {
+	record := &api.Record{
+		ID: "foo",
+		Data: map[string]any{
+			"source": "CRM",
+			"age":    float64(42),
+		},
+	}
+
+	actual, err := api.MatchesAll(nil, record)
+	require.NoError(t, err)
+	assert.True(t, actual)
+
+	actual, err = api.MatchesAll([]*api.FilterCondition{
+		{Path: "source", Equals: "CRM"},
+		{Path: "age", GreaterThan: pointer(40.0)},
+	}, record)
+	require.NoError(t, err)
+	assert.True(t, actual)
+
+	actual, err = api.MatchesAll([]*api.FilterCondition{
+		{Path: "source", Equals: "CRM"},
+		{Path: "age", GreaterThan: pointer(50.0)},
+	}, record)
+	require.NoError(t, err)
+	assert.False(t, actual)
+}
+
+func TestFilterExpressionMatches(t *testing.T) {
+	crm := &api.Record{ID: "1", Data: map[string]any{"source": "CRM", "submitted": "2023-01-01T00:00:00Z"}}
+	oldERP := &api.Record{ID: "2", Data: map[string]any{"source": "ERP", "submitted": "2023-01-01T00:00:00Z"}}
+	newERP := &api.Record{ID: "3", Data: map[string]any{"source": "ERP", "submitted": "2025-01-01T00:00:00Z"}}
+	other := &api.Record{ID: "4", Data: map[string]any{"source": "WEB", "submitted": "2025-01-01T00:00:00Z"}}
+	x := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	expression := api.FilterOr(
+		api.NewFilterExpression(&api.FilterCondition{Path: "source", Equals: "CRM"}),
+		api.NewFilterExpression(
+			&api.FilterCondition{Path: "source", Equals: "ERP"},
+			&api.FilterCondition{Path: "submitted", After: &x},
+		),
+	)
+	for record, expected := range map[*api.Record]bool{crm: true, oldERP: false, newERP: true, other: false} {
+		actual, err := expression.Matches(record)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual, record.ID)
+	}
+
+	expression = api.FilterNot(expression)
+	for record, expected := range map[*api.Record]bool{crm: false, oldERP: true, newERP: false, other: true} {
+		actual, err := expression.Matches(record)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual, record.ID)
+	}
+
+	var empty *api.FilterExpression
+	actual, err := empty.Matches(crm)
+	require.NoError(t, err)
+	assert.True(t, actual)
+}
+
+func TestCombineFilters(t *testing.T) {
+	crm := &api.Record{ID: "1", Data: map[string]any{"source": "CRM", "age": float64(42)}}
+	young := &api.Record{ID: "2", Data: map[string]any{"source": "CRM", "age": float64(17)}}
+	erp := &api.Record{ID: "3", Data: map[string]any{"source": "ERP", "age": float64(42)}}
+	conditions := []*api.FilterCondition{{Path: "source", Equals: "CRM"}}
+	expression := api.FilterNot(api.NewFilterExpression(&api.FilterCondition{Path: "age", LessThan: pointer(18.0)}))
+
+	cases := map[string]struct {
+		conditions []*api.FilterCondition
+		expression *api.FilterExpression
+		expected   map[*api.Record]bool
+	}{
+		"none": {
+			expected: map[*api.Record]bool{crm: true, young: true, erp: true},
+		},
+		"conditions only": {
+			conditions: conditions,
+			expected:   map[*api.Record]bool{crm: true, young: true, erp: false},
+		},
+		"expression only": {
+			expression: expression,
+			expected:   map[*api.Record]bool{crm: true, young: false, erp: true},
+		},
+		"both": {
+			conditions: conditions,
+			expression: expression,
+			expected:   map[*api.Record]bool{crm: true, young: false, erp: false},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			combined := api.CombineFilters(c.conditions, c.expression)
+			for record, expected := range c.expected {
+				actual, err := combined.Matches(record)
+				require.NoError(t, err)
+				assert.Equal(t, expected, actual, record.ID)
+			}
+		})
+	}
+}
+
+func TestFilterExpressionJSON(t *testing.T) {
+	cases := map[string]struct {
+		input    string
+		expected *api.FilterExpression
+		isList   bool
+	}{
+		"flat list": {
+			input: `[{"path": "source", "equals": "CRM"}, {"path": "age", "greaterThan": 18}]`,
+			expected: api.NewFilterExpression(
+				&api.FilterCondition{Path: "source", Equals: "CRM"},
+				&api.FilterCondition{Path: "age", GreaterThan: pointer(18.0)},
+			),
+			isList: true,
+		},
+		"empty list": {
+			input:    `[]`,
+			expected: api.NewFilterExpression(),
+			isList:   true,
+		},
+		"composite": {
+			input: `{"or": [{"condition": {"path": "source", "equals": "CRM"}}, {"not": {"condition": {"path": "source"}}}]}`,
+			expected: api.FilterOr(
+				&api.FilterExpression{Condition: &api.FilterCondition{Path: "source", Equals: "CRM"}},
+				api.FilterNot(&api.FilterExpression{Condition: &api.FilterCondition{Path: "source"}}),
+			),
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual := &api.FilterExpression{}
+			err := json.Unmarshal([]byte(c.input), actual)
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+
+			marshalled, err := json.Marshal(actual)
+			require.NoError(t, err)
+			assert.Equal(t, c.isList, marshalled[0] == '[')
+
+			roundTrip := &api.FilterExpression{}
+			err = json.Unmarshal(marshalled, roundTrip)
+			require.NoError(t, err)
+			remarshalled, err := json.Marshal(roundTrip)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(marshalled), string(remarshalled))
+		})
+	}
+}
+
+func pointer[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"sort"
+)
+
+// EntityGraph is a graph view on an Entity.
+//
+// The records of the entity are the nodes of the graph and its edges are the
+// undirected connections between them. Nodes are identified by the record ID
+// without its version, i.e. the ID of the Record. Multiple edges between the
+// same two records (e.g. from different rules) are kept, edges connecting a
+// record with itself are ignored.
+//
+// Records are also connected with their Duplicates, if both are part of the
+// graph. These connections are not edges, but are taken into account by
+// ConnectedComponents and ArticulationEdges.
+type EntityGraph struct {
+	ids        []string
+	adjacency  map[string]map[string][]Edge
+	duplicates map[string]map[string]bool
+}
+
+// NewEntityGraph creates a new graph view on the given entity.
+//
+// An error is returned if any of the entities edges or duplicate keys cannot
+// be parsed.
+func NewEntityGraph(entity *Entity) (*EntityGraph, error) {
+	g := &EntityGraph{
+		adjacency:  map[string]map[string][]Edge{},
+		duplicates: map[string]map[string]bool{},
+	}
+	for _, record := range entity.Records {
+		g.addNode(record.ID)
+	}
+	edges, err := entity.Edges.Parse()
+	if err != nil {
+		return nil, err
+	}
+	for _, edge := range edges {
+		a, b := edge.A.ID, edge.B.ID
+		g.addNode(a)
+		g.addNode(b)
+		if a == b {
+			continue
+		}
+		g.adjacency[a][b] = append(g.adjacency[a][b], edge)
+		g.adjacency[b][a] = append(g.adjacency[b][a], edge)
+	}
+	for key, duplicates := range entity.Duplicates {
+		rid, _, err := ParseDuplicateKeyStrict(key)
+		if err != nil {
+			return nil, err
+		}
+		a, err := ParseRecordIDStrict(rid)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range duplicates {
+			g.addDuplicate(a.ID, b)
+		}
+	}
+	sort.Strings(g.ids)
+	return g, nil
+}
+
+func (g *EntityGraph) addDuplicate(a, b string) {
+	_, okA := g.adjacency[a]
+	_, okB := g.adjacency[b]
+	if !okA || !okB || a == b {
+		return
+	}
+	if g.duplicates[a] == nil {
+		g.duplicates[a] = map[string]bool{}
+	}
+	if g.duplicates[b] == nil {
+		g.duplicates[b] = map[string]bool{}
+	}
+	g.duplicates[a][b] = true
+	g.duplicates[b][a] = true
+}
+
+// connections returns the sorted IDs of all records that are directly
+// connected with the given record, either by an edge or as duplicates.
+func (g *EntityGraph) connections(recordID string) []string {
+	connections := g.Neighbors(recordID)
+	for id := range g.duplicates[recordID] {
+		if _, ok := g.adjacency[recordID][id]; !ok {
+			connections = append(connections, id)
+		}
+	}
+	sort.Strings(connections)
+	return connections
+}
+
+func (g *EntityGraph) addNode(id string) {
+	if _, ok := g.adjacency[id]; ok {
+		return
+	}
+	g.adjacency[id] = map[string][]Edge{}
+	g.ids = append(g.ids, id)
+}
+
+// RecordIDs returns the sorted IDs of all records in the graph.
+func (g *EntityGraph) RecordIDs() []string {
+	return append([]string{}, g.ids...)
+}
+
+// Neighbors returns the sorted IDs of all records that are directly connected
+// with the given record.
+func (g *EntityGraph) Neighbors(recordID string) []string {
+	neighbors := make([]string, 0, len(g.adjacency[recordID]))
+	for id := range g.adjacency[recordID] {
+		neighbors = append(neighbors, id)
+	}
+	sort.Strings(neighbors)
+	return neighbors
+}
+
+// EdgesBetween returns all edges that directly connect the two records.
+func (g *EntityGraph) EdgesBetween(a, b string) []Edge {
+	return append([]Edge{}, g.adjacency[a][b]...)
+}
+
+// ConnectedComponents returns the groups of records that are connected with
+// each other, either directly or through other records. Records are connected
+// by edges and with their duplicates.
+//
+// Each group is sorted and the groups are sorted by their first record ID. An
+// entity is expected to have exactly one connected component.
+func (g *EntityGraph) ConnectedComponents() [][]string {
+	visited := map[string]bool{}
+	components := [][]string{}
+	for _, id := range g.ids {
+		if visited[id] {
+			continue
+		}
+		component := []string{}
+		queue := []string{id}
+		visited[id] = true
+		for len(queue) != 0 {
+			current := queue[0]
+			queue = queue[1:]
+			component = append(component, current)
+			for _, neighbor := range g.connections(current) {
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+		sort.Strings(component)
+		components = append(components, component)
+	}
+	return components
+}
+
+// ShortestPath returns the edges along one of the shortest paths between the
+// two records.
+//
+// The edges are oriented along the path, i.e. A of the first edge is the from
+// record, B of each edge is A of the next edge and B of the last edge is the
+// to record. If two records are connected by multiple edges, then the edge
+// with the highest score is used.
+//
+// The second return value is false if there is no path between the records.
+func (g *EntityGraph) ShortestPath(from, to string) ([]Edge, bool) {
+	if _, ok := g.adjacency[from]; !ok {
+		return nil, false
+	}
+	previous := map[string]string{from: from}
+	queue := []string{from}
+	for len(queue) != 0 && queue[0] != to {
+		current := queue[0]
+		queue = queue[1:]
+		for _, neighbor := range g.Neighbors(current) {
+			if _, ok := previous[neighbor]; !ok {
+				previous[neighbor] = current
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+	if _, ok := previous[to]; !ok {
+		return nil, false
+	}
+	path := []Edge{}
+	for current := to; current != from; current = previous[current] {
+		path = append(path, g.bestEdge(previous[current], current))
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, true
+}
+
+// bestEdge returns the edge with the highest score between a and b, oriented
+// from a to b.
+func (g *EntityGraph) bestEdge(a, b string) Edge {
+	var best Edge
+	for i, edge := range g.adjacency[a][b] {
+		if i == 0 || edge.Score > best.Score || (edge.Score == best.Score && edge.Rule < best.Rule) {
+			best = edge
+		}
+	}
+	if best.A.ID != a {
+		best.A, best.B = best.B, best.A
+	}
+	return best
+}
+
+// ArticulationEdges returns all edges whose removal would split the entity
+// into multiple entities.
+//
+// The entity is split once all edges between two records are removed. Hence
+// if two records are connected by more than one edge, either all or none of
+// these edges are returned. Edges between records that are also duplicates of
+// each other are never returned. The returned edges are sorted by their string
+// representation.
+func (g *EntityGraph) ArticulationEdges() []Edge {
+	discovery := map[string]int{}
+	low := map[string]int{}
+	bridges := []Edge{}
+	var visit func(id, parent string)
+	visit = func(id, parent string) {
+		discovery[id] = len(discovery) + 1
+		low[id] = discovery[id]
+		for _, neighbor := range g.connections(id) {
+			if neighbor == parent {
+				continue
+			}
+			if _, ok := discovery[neighbor]; ok {
+				low[id] = min(low[id], discovery[neighbor])
+				continue
+			}
+			visit(neighbor, id)
+			low[id] = min(low[id], low[neighbor])
+			if low[neighbor] > discovery[id] && !g.duplicates[id][neighbor] {
+				bridges = append(bridges, g.adjacency[id][neighbor]...)
+			}
+		}
+	}
+	for _, id := range g.ids {
+		if _, ok := discovery[id]; !ok {
+			visit(id, "")
+		}
+	}
+	sort.Slice(bridges, func(i, j int) bool {
+		return bridges[i].String() < bridges[j].String()
+	})
+	return bridges
+}
@@ -0,0 +1,117 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// graphEntity is connected as follows:
+//
+//	r1 -R1- r2 -R2- r3 -R1- r4
+//	 \______R3_____/        |
+//	                        R1 and R2
+//	                        |
+//	                        r5
+func graphEntity() *api.Entity {
+	return &api.Entity{
+		ID: "e1",
+		Records: []*api.Record{
+			{ID: "r1"}, {ID: "r2"}, {ID: "r3"}, {ID: "r4"}, {ID: "r5"}, {ID: "r6"},
+		},
+		Edges: api.Edges{
+			"r1:0:r2:0:R1:90",
+			"r2:0:r3:1:R2:80",
+			"r1:r3:R3",
+			"r3:1:r4:0:R1:70",
+			"r4:r5:R1",
+			"r5:r4:R2",
+			"r6:r6:STATIC",
+		},
+	}
+}
+
+func TestEntityGraphNeighbors(t *testing.T) {
+	g, err := api.NewEntityGraph(graphEntity())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"r1", "r2", "r3", "r4", "r5", "r6"}, g.RecordIDs())
+	assert.Equal(t, []string{"r1", "r2", "r4"}, g.Neighbors("r3"))
+	assert.Equal(t, []string{"r4"}, g.Neighbors("r5"))
+	assert.Empty(t, g.Neighbors("r6"))
+	assert.Empty(t, g.Neighbors("unknown"))
+	assert.Len(t, g.EdgesBetween("r4", "r5"), 2)
+}
+
+func TestEntityGraphConnectedComponents(t *testing.T) {
+	g, err := api.NewEntityGraph(graphEntity())
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{{"r1", "r2", "r3", "r4", "r5"}, {"r6"}}, g.ConnectedComponents())
+}
+
+func TestEntityGraphShortestPath(t *testing.T) {
+	g, err := api.NewEntityGraph(graphEntity())
+	require.NoError(t, err)
+
+	path, ok := g.ShortestPath("r1", "r5")
+	require.True(t, ok)
+	assert.Equal(t, []api.Edge{
+		{A: api.RecordID{ID: "r1"}, B: api.RecordID{ID: "r3"}, Rule: "R3", Score: 100},
+		{A: api.RecordID{ID: "r3", Version: 1}, B: api.RecordID{ID: "r4"}, Rule: "R1", Score: 70},
+		{A: api.RecordID{ID: "r4"}, B: api.RecordID{ID: "r5"}, Rule: "R1", Score: 100},
+	}, path)
+
+	path, ok = g.ShortestPath("r2", "r1")
+	require.True(t, ok)
+	assert.Equal(t, []api.Edge{{A: api.RecordID{ID: "r2"}, B: api.RecordID{ID: "r1"}, Rule: "R1", Score: 90}}, path)
+
+	path, ok = g.ShortestPath("r1", "r1")
+	require.True(t, ok)
+	assert.Empty(t, path)
+
+	_, ok = g.ShortestPath("r1", "r6")
+	assert.False(t, ok)
+
+	_, ok = g.ShortestPath("unknown", "r1")
+	assert.False(t, ok)
+}
+
+func TestEntityGraphArticulationEdges(t *testing.T) {
+	g, err := api.NewEntityGraph(graphEntity())
+	require.NoError(t, err)
+
+	expected := []api.Edge{
+		{A: api.RecordID{ID: "r3", Version: 1}, B: api.RecordID{ID: "r4"}, Rule: "R1", Score: 70},
+		{A: api.RecordID{ID: "r4"}, B: api.RecordID{ID: "r5"}, Rule: "R1", Score: 100},
+		{A: api.RecordID{ID: "r5"}, B: api.RecordID{ID: "r4"}, Rule: "R2", Score: 100},
+	}
+	assert.Equal(t, expected, g.ArticulationEdges())
+}
+
+func TestEntityGraphDuplicates(t *testing.T) {
+	g, err := api.NewEntityGraph(&api.Entity{
+		Records: []*api.Record{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}},
+		Edges:   api.Edges{"a:b:R1", "b:c:R1"},
+		Duplicates: api.Duplicates{
+			":a:0": []string{"d", "unknown"},
+			":b:0": []string{"c"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{{"a", "b", "c", "d"}}, g.ConnectedComponents())
+	assert.Equal(t, []string{"a", "c"}, g.Neighbors("b"))
+	assert.Empty(t, g.Neighbors("d"))
+	assert.Equal(t, []api.Edge{{A: api.RecordID{ID: "a"}, B: api.RecordID{ID: "b"}, Rule: "R1", Score: 100}}, g.ArticulationEdges())
+
+	_, err = api.NewEntityGraph(&api.Entity{Duplicates: api.Duplicates{":a:x": []string{"b"}}})
+	assert.Error(t, err)
+}
+
+func TestNewEntityGraphInvalidEdge(t *testing.T) {
+	_, err := api.NewEntityGraph(&api.Entity{Edges: api.Edges{"invalid"}})
+	assert.ErrorIs(t, err, api.ErrInvalidEdge)
+}
@@ -0,0 +1,147 @@
+// Package values converts and compares the values of record data.
+package values
+
+import (
+	"cmp"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Float converts the value into a number.
+//
+// All Go number types, json.Number and strings containing a number are
+// supported. The second return value is false for all other values.
+func Float(value any) (float64, bool) {
+	if s, ok := value.(string); ok {
+		n, err := strconv.ParseFloat(s, 64)
+		return n, err == nil
+	}
+	return number(value)
+}
+
+func number(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// Time converts the value into a time.
+//
+// time.Time, *time.Time and strings in the RFC 3339 format are supported. The
+// second return value is false for all other values.
+func Time(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, false
+		}
+		return *v, true
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// Normalize converts the value into a float64, time.Time, string or bool, so
+// that equal values are equal using == and can be compared using Compare.
+//
+// Numbers are converted into float64. Times and strings in the RFC 3339 format
+// are converted into a time.Time in UTC. Other than for Float, strings
+// containing a number remain strings. nil is returned for all values that are
+// neither numbers, times, strings nor booleans, e.g. lists or maps.
+func Normalize(value any) any {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string, time.Time, *time.Time:
+		if t, ok := Time(v); ok {
+			return t.UTC().Round(0)
+		}
+		if s, ok := v.(string); ok {
+			return s
+		}
+		return nil
+	}
+	if n, ok := number(value); ok {
+		return n
+	}
+	return nil
+}
+
+// Compare compares two normalized values.
+//
+// Values of the same type are compared by their natural order. Otherwise,
+// numbers are sorted before times, times before strings and strings before
+// booleans.
+func Compare(a, b any) int {
+	if c := cmp.Compare(kind(a), kind(b)); c != 0 {
+		return c
+	}
+	switch v := a.(type) {
+	case float64:
+		return cmp.Compare(v, b.(float64))
+	case time.Time:
+		return v.Compare(b.(time.Time))
+	case string:
+		return strings.Compare(v, b.(string))
+	case bool:
+		return compareBool(v, b.(bool))
+	}
+	return 0
+}
+
+func kind(value any) int {
+	switch value.(type) {
+	case float64:
+		return 0
+	case time.Time:
+		return 1
+	case string:
+		return 2
+	case bool:
+		return 3
+	}
+	return 4
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
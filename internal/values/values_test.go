@@ -0,0 +1,72 @@
+package values_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tilotech/tilores-plugin-api/internal/values"
+)
+
+func TestConversion(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	half, five := 1.5, 5.0
+
+	cases := map[string]struct {
+		value      any
+		float      *float64
+		time       *time.Time
+		normalized any
+	}{
+		"float64":      {value: 1.5, float: &half, normalized: 1.5},
+		"int":          {value: 5, float: &five, normalized: 5.0},
+		"uint8":        {value: uint8(5), float: &five, normalized: 5.0},
+		"json number":  {value: json.Number("5"), float: &five, normalized: 5.0},
+		"number text":  {value: "5", float: &five, normalized: "5"},
+		"time":         {value: ts, time: &ts, normalized: ts},
+		"time pointer": {value: &ts, time: &ts, normalized: ts},
+		"time text":    {value: "2024-01-01T00:00:00Z", time: &ts, normalized: ts},
+		"text":         {value: "foo", normalized: "foo"},
+		"bool":         {value: true, normalized: true},
+		"nil":          {value: nil},
+		"nil time":     {value: (*time.Time)(nil)},
+		"list":         {value: []any{1.0}},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			f, ok := values.Float(c.value)
+			assert.Equal(t, c.float != nil, ok)
+			if c.float != nil {
+				assert.Equal(t, *c.float, f)
+			}
+			actualTime, ok := values.Time(c.value)
+			assert.Equal(t, c.time != nil, ok)
+			if c.time != nil {
+				assert.True(t, c.time.Equal(actualTime))
+			}
+			assert.Equal(t, c.normalized, values.Normalize(c.value))
+		})
+	}
+}
+
+func TestNormalizeTimeLocation(t *testing.T) {
+	utc := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	berlin := utc.In(time.FixedZone("CET", 3600))
+
+	assert.True(t, values.Normalize(utc) == values.Normalize(berlin))
+	assert.True(t, values.Normalize("2024-01-01T13:00:00+01:00") == values.Normalize(utc))
+}
+
+func TestCompare(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, -1, values.Compare(9.0, 10.0))
+	assert.Equal(t, 1, values.Compare("b", "a"))
+	assert.Equal(t, 0, values.Compare(ts, ts))
+	assert.Equal(t, -1, values.Compare(false, true))
+	assert.Equal(t, -1, values.Compare(10.0, ts))
+	assert.Equal(t, -1, values.Compare(ts, "a"))
+	assert.Equal(t, -1, values.Compare("a", false))
+}
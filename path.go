@@ -0,0 +1,286 @@
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Path references zero or more values within the (nested) data of a record.
+//
+// The textual representation of a path uses the following syntax:
+//
+//	name.first          key "first" within the map at key "name"
+//	emails[0]           first element of the list at key "emails"
+//	addresses[*].zip    key "zip" of every element of the list at key "addresses"
+//	tags[*]             every element of the list at key "tags"
+//	my\.key.value       key "value" within the map at key "my.key"
+//
+// Keys are separated by dots. List elements are accessed using either a non
+// negative index or the wildcard "*" in square brackets. A backslash escapes
+// the following character, which allows keys that contain dots, brackets or
+// backslashes. An empty path references the data itself.
+type Path []PathSegment
+
+// PathSegment is a single step within a Path.
+type PathSegment struct {
+	Kind  PathSegmentKind
+	Key   string
+	Index int
+}
+
+// PathSegmentKind defines how a PathSegment accesses the data.
+type PathSegmentKind int
+
+const (
+	// PathKey accesses the value of a map using the segments Key.
+	PathKey PathSegmentKind = iota
+	// PathIndex accesses the element of a list using the segments Index.
+	PathIndex
+	// PathWildcard accesses all elements of a list.
+	PathWildcard
+)
+
+// ParsePath parses the textual representation of a path.
+//
+// An error is returned if the path does not follow the syntax described in
+// Path.
+func ParsePath(path string) (Path, error) {
+	p := Path{}
+	for i := 0; i < len(path); {
+		switch {
+		case path[i] == '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid path %q: missing closing bracket", path)
+			}
+			segment, err := parseBracketSegment(path[i+1 : i+end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %q: %w", path, err)
+			}
+			p = append(p, segment)
+			i += end + 1
+		case path[i] == '.' && len(p) != 0:
+			key, n, err := parseKey(path[i+1:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %q: %w", path, err)
+			}
+			p = append(p, PathSegment{Kind: PathKey, Key: key})
+			i += n + 1
+		default:
+			if len(p) != 0 {
+				return nil, fmt.Errorf("invalid path %q: unexpected character at position %v", path, i)
+			}
+			key, n, err := parseKey(path)
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %q: %w", path, err)
+			}
+			p = append(p, PathSegment{Kind: PathKey, Key: key})
+			i += n
+		}
+	}
+	return p, nil
+}
+
+func parseBracketSegment(s string) (PathSegment, error) {
+	if s == "*" {
+		return PathSegment{Kind: PathWildcard}, nil
+	}
+	index, err := strconv.Atoi(s)
+	if err != nil || index < 0 {
+		return PathSegment{}, fmt.Errorf("invalid list index %q", s)
+	}
+	return PathSegment{Kind: PathIndex, Index: index}, nil
+}
+
+// parseKey reads an unescaped key from the beginning of s and returns the key
+// and the number of consumed bytes.
+func parseKey(s string) (string, int, error) {
+	key := strings.Builder{}
+	i := 0
+	for ; i < len(s); i++ {
+		c := s[i]
+		if c == '.' || c == '[' {
+			break
+		}
+		if c == ']' {
+			return "", 0, fmt.Errorf("unexpected closing bracket")
+		}
+		if c == '\\' {
+			i++
+			if i == len(s) {
+				return "", 0, fmt.Errorf("incomplete escape sequence")
+			}
+			c = s[i]
+		}
+		key.WriteByte(c)
+	}
+	if i == 0 {
+		return "", 0, fmt.Errorf("empty key")
+	}
+	return key.String(), i, nil
+}
+
+// String returns the textual representation of the path.
+func (p Path) String() string {
+	s := strings.Builder{}
+	for i, segment := range p {
+		switch segment.Kind {
+		case PathKey:
+			if i != 0 {
+				s.WriteByte('.')
+			}
+			s.WriteString(escapePathKey(segment.Key))
+		case PathIndex:
+			s.WriteString(fmt.Sprintf("[%v]", segment.Index))
+		case PathWildcard:
+			s.WriteString("[*]")
+		}
+	}
+	return s.String()
+}
+
+func escapePathKey(key string) string {
+	s := strings.Builder{}
+	for i := 0; i < len(key); i++ {
+		switch key[i] {
+		case '.', '[', ']', '\\':
+			s.WriteByte('\\')
+		}
+		s.WriteByte(key[i])
+	}
+	return s.String()
+}
+
+// Resolve returns all values found at the path within the provided data.
+//
+// Segments that cannot be applied, e.g. a key on a list or a missing index,
+// do not produce any values. A nil value that is present in the data is
+// returned as nil.
+func (p Path) Resolve(data any) []any {
+	values := []any{data}
+	for _, segment := range p {
+		next := []any{}
+		for _, value := range values {
+			next = append(next, segment.resolve(value)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func (s PathSegment) resolve(value any) []any {
+	if s.Kind == PathKey {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v, ok := m[s.Key]
+		if !ok {
+			return nil
+		}
+		return []any{v}
+	}
+	list := toList(value)
+	if s.Kind == PathWildcard {
+		return list
+	}
+	if s.Index >= len(list) {
+		return nil
+	}
+	return []any{list[s.Index]}
+}
+
+func toList(value any) []any {
+	if list, ok := value.([]any); ok {
+		return list
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil
+	}
+	list := make([]any, rv.Len())
+	for i := range list {
+		list[i] = rv.Index(i).Interface()
+	}
+	return list
+}
+
+// ResolvePath returns all values found at the path within the records data.
+//
+// See Path for the supported syntax. An error is returned if the path is
+// invalid.
+func ResolvePath(record *Record, path string) ([]any, error) {
+	p, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || record.Data == nil {
+		return []any{}, nil
+	}
+	return p.Resolve(record.Data), nil
+}
+
+// SelectPaths returns a copy of the data that only contains the values
+// referenced by the given paths.
+//
+// The structure of the data is kept, i.e. the selected values remain nested
+// at the same keys. Lists only contain the selected elements in their original
+// order, hence the indices of the elements may change. Maps and lists from
+// which nothing was selected are omitted.
+func SelectPaths(data map[string]any, paths ...Path) map[string]any {
+	selected, ok := selectPaths(data, paths)
+	if !ok {
+		return map[string]any{}
+	}
+	return selected.(map[string]any)
+}
+
+func selectPaths(value any, paths []Path) (any, bool) {
+	for _, path := range paths {
+		if len(path) == 0 {
+			return value, true
+		}
+	}
+	if m, ok := value.(map[string]any); ok {
+		return selectMapPaths(m, paths)
+	}
+	list := toList(value)
+	if list == nil {
+		return nil, false
+	}
+	selected := []any{}
+	for i, element := range list {
+		rest := []Path{}
+		for _, path := range paths {
+			if path[0].Kind == PathWildcard || (path[0].Kind == PathIndex && path[0].Index == i) {
+				rest = append(rest, path[1:])
+			}
+		}
+		if v, ok := selectPaths(element, rest); ok {
+			selected = append(selected, v)
+		}
+	}
+	return selected, len(selected) != 0
+}
+
+func selectMapPaths(m map[string]any, paths []Path) (any, bool) {
+	rest := map[string][]Path{}
+	for _, path := range paths {
+		if path[0].Kind == PathKey {
+			rest[path[0].Key] = append(rest[path[0].Key], path[1:])
+		}
+	}
+	selected := map[string]any{}
+	for key, keyPaths := range rest {
+		value, ok := m[key]
+		if !ok {
+			continue
+		}
+		if v, ok := selectPaths(value, keyPaths); ok {
+			selected[key] = v
+		}
+	}
+	return selected, len(selected) != 0
+}
@@ -0,0 +1,192 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestParsePath(t *testing.T) {
+	cases := map[string]struct {
+		input       string
+		expected    api.Path
+		expectError bool
+	}{
+		"empty": {
+			input:    "",
+			expected: api.Path{},
+		},
+		"single key": {
+			input:    "name",
+			expected: api.Path{{Kind: api.PathKey, Key: "name"}},
+		},
+		"nested keys": {
+			input: "name.first",
+			expected: api.Path{
+				{Kind: api.PathKey, Key: "name"},
+				{Kind: api.PathKey, Key: "first"},
+			},
+		},
+		"index and wildcard": {
+			input: "addresses[*].lines[0]",
+			expected: api.Path{
+				{Kind: api.PathKey, Key: "addresses"},
+				{Kind: api.PathWildcard},
+				{Kind: api.PathKey, Key: "lines"},
+				{Kind: api.PathIndex, Index: 0},
+			},
+		},
+		"escaped key": {
+			input: `my\.key.a\[b\]\\`,
+			expected: api.Path{
+				{Kind: api.PathKey, Key: "my.key"},
+				{Kind: api.PathKey, Key: `a[b]\`},
+			},
+		},
+		"leading index": {
+			input:    "[1]",
+			expected: api.Path{{Kind: api.PathIndex, Index: 1}},
+		},
+		"empty key":         {input: "name..first", expectError: true},
+		"trailing dot":      {input: "name.", expectError: true},
+		"leading dot":       {input: ".name", expectError: true},
+		"missing bracket":   {input: "list[0", expectError: true},
+		"negative index":    {input: "list[-1]", expectError: true},
+		"invalid index":     {input: "list[a]", expectError: true},
+		"key after index":   {input: "list[0]name", expectError: true},
+		"unexpected close":  {input: "list]", expectError: true},
+		"incomplete escape": {input: `name\`, expectError: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := api.ParsePath(c.input)
+			if c.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+			assert.Equal(t, c.input, actual.String())
+		})
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	record := &api.Record{
+		ID: "foo",
+		Data: map[string]any{
+			"name": map[string]any{
+				"first": "Jane",
+			},
+			"addresses": []any{
+				map[string]any{"zip": "12345"},
+				map[string]any{"zip": "67890"},
+				map[string]any{"city": "Berlin"},
+			},
+			"tags":   []string{"a", "b"},
+			"my.key": nil,
+		},
+	}
+
+	cases := map[string][]any{
+		"name.first":        {"Jane"},
+		"name.last":         {},
+		"addresses[*].zip":  {"12345", "67890"},
+		"addresses[1].zip":  {"67890"},
+		"addresses[5].zip":  {},
+		"addresses.zip":     {},
+		"tags[*]":           {"a", "b"},
+		"tags[1]":           {"b"},
+		"name[0]":           {},
+		`my\.key`:           {nil},
+		"addresses[*].city": {"Berlin"},
+	}
+
+	for path, expected := range cases {
+		t.Run(path, func(t *testing.T) {
+			actual, err := api.ResolvePath(record, path)
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+	}
+
+	_, err := api.ResolvePath(record, "name..first")
+	assert.Error(t, err)
+
+	actual, err := api.ResolvePath(nil, "name")
+	require.NoError(t, err)
+	assert.Empty(t, actual)
+}
+
+func TestSelectPaths(t *testing.T) {
+	data := map[string]any{
+		"name": map[string]any{
+			"first": "Jane",
+			"last":  "Doe",
+		},
+		"address": []any{
+			map[string]any{"zip": "12345", "city": "Berlin"},
+			map[string]any{"zip": "67890", "city": "Hamburg"},
+			map[string]any{"city": "Munich"},
+		},
+		"tags": []any{"a", "b", "c"},
+	}
+
+	cases := map[string]struct {
+		paths    []string
+		expected map[string]any
+	}{
+		"nested key": {
+			paths: []string{"name.last"},
+			expected: map[string]any{
+				"name": map[string]any{"last": "Doe"},
+			},
+		},
+		"wildcard": {
+			paths: []string{"address[*].zip"},
+			expected: map[string]any{
+				"address": []any{
+					map[string]any{"zip": "12345"},
+					map[string]any{"zip": "67890"},
+				},
+			},
+		},
+		"index": {
+			paths: []string{"address[1].city", "tags[2]"},
+			expected: map[string]any{
+				"address": []any{map[string]any{"city": "Hamburg"}},
+				"tags":    []any{"c"},
+			},
+		},
+		"overlapping": {
+			paths: []string{"name", "name.first", "address[0].zip", "address[0].city"},
+			expected: map[string]any{
+				"name":    data["name"],
+				"address": []any{map[string]any{"zip": "12345", "city": "Berlin"}},
+			},
+		},
+		"missing": {
+			paths:    []string{"name.middle", "unknown", "tags.key"},
+			expected: map[string]any{},
+		},
+		"none": {
+			paths:    []string{},
+			expected: map[string]any{},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			paths := make([]api.Path, len(c.paths))
+			for i, p := range c.paths {
+				path, err := api.ParsePath(p)
+				require.NoError(t, err)
+				paths[i] = path
+			}
+			assert.Equal(t, c.expected, api.SelectPaths(data, paths...))
+		})
+	}
+}